@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"time"
 
@@ -10,8 +11,11 @@ import (
 )
 
 var (
-	rawSeed  uint64
-	rawBytes int
+	rawSeed   uint64
+	rawBytes  int
+	rawGen    string
+	rawRounds int
+	rawMixer  string
 )
 
 var rawCmd = &cobra.Command{
@@ -37,48 +41,66 @@ Examples:
   # Test randomness with ent
   r30r2 raw --bytes 1048576 | ent
 
+  # Rule 30 without an output mixer (research)
+  r30r2 raw --mixer identity --bytes 0 | ./testu01/test-smallcrush
+
+  # Counter-based generator with fewer rounds (quality research)
+  r30r2 raw --gen counter --rounds 16 --bytes 0 | ./testu01/test-smallcrush
+
   # Default behavior (no subcommand)
-  r30r2 --bytes 1024 > random.bin`,
+  r30r2 --bytes 1024 > random.bin
+
+Generators (--gen):
+` + generatorHelp(),
 	Run: func(cmd *cobra.Command, args []string) {
 		// Use time-based seed if not specified
 		if rawSeed == 0 {
 			rawSeed = uint64(time.Now().UnixNano())
 		}
 
-		generateBytes(rawSeed, rawBytes)
+		if rawRounds < 0 {
+			fmt.Fprintf(os.Stderr, "Error: --rounds must be non-negative\n")
+			os.Exit(1)
+		}
+
+		mixer, err := rand.ParseMixer(rawMixer)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		src, err := newGenerator(rawGen, rawSeed)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if mixer != rand.MixGolden {
+			if _, ok := src.(*rand.RNG); !ok {
+				fmt.Fprintf(os.Stderr, "Error: --mixer only applies to --gen=rule30\n")
+				os.Exit(1)
+			}
+			src = rand.NewMixed(rawSeed, mixer)
+		}
+
+		generateBytes(src, rawBytes)
 	},
 }
 
 func init() {
 	rawCmd.Flags().Uint64Var(&rawSeed, "seed", 0, "RNG seed (default: time-based)")
 	rawCmd.Flags().IntVar(&rawBytes, "bytes", 1024, "Number of bytes to generate (0 = unlimited)")
+	rawCmd.Flags().StringVar(&rawGen, "gen", "rule30", "Generator to stream (see list above)")
+	rawCmd.Flags().StringVar(&rawMixer, "mixer", "golden", "Output mixer for --gen=rule30: golden, identity, splitmix64, murmur3, moremur")
+	rawCmd.Flags().IntVar(&rawRounds, "rounds", rand.DefaultCounterRounds, "Rule 30 steps per block for --gen=counter")
 }
 
-// generateBytes generates and writes random bytes to stdout
-func generateBytes(seed uint64, count int) {
-	rng := rand.New(seed)
-
+// generateBytes reads random bytes from rng and writes them to stdout
+func generateBytes(rng io.Reader, count int) {
 	if count == 0 {
-		// Unlimited mode: stream chunks until pipe breaks
-		buf := make([]byte, 1024*1024) // 1MB chunks
-		for {
-			n, err := rng.Read(buf)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				os.Exit(1)
-			}
-
-			// Write all bytes, handling partial writes
-			written := 0
-			for written < n {
-				w, err := os.Stdout.Write(buf[written:n])
-				if err != nil {
-					// Pipe closed (e.g., dd finished) - exit gracefully
-					os.Exit(0)
-				}
-				written += w
-			}
-		}
+		// Unlimited mode: stream until pipe breaks
+		// io.Copy only returns once stdout fails (e.g., dd finished) - exit gracefully
+		io.Copy(os.Stdout, rng)
+		os.Exit(0)
 	} else {
 		// Fixed size: stream in chunks to avoid huge allocations
 		const chunkSize = 1024 * 1024 // 1MB chunks
@@ -12,7 +12,7 @@ var rootCmd = &cobra.Command{
 	Long: `R30R2 - Random Number Generator using Rule 30 Cellular Automaton
 
 A deterministic RNG based on 1D cellular automata (Rule 30).
-Uses a circular 256-bit strip with radius-2 Rule 30 evolution rules.
+Uses a circular 256-bit strip with radius-1 Rule 30 evolution rules.
 
 Known for generating high-quality pseudo-randomness.
 Passes all 319 TestU01 tests including complete BigCrush suite.`,
@@ -31,7 +31,7 @@ func Execute() error {
 	if len(os.Args) > 1 {
 		firstArg := os.Args[1]
 		// Check if it's a known subcommand or help/version flag
-		if firstArg != "raw" && firstArg != "ascii" &&
+		if firstArg != "raw" && firstArg != "ascii" && firstArg != "vectors" &&
 		   firstArg != "version" && firstArg != "help" && firstArg != "completion" &&
 		   firstArg != "-h" && firstArg != "--help" {
 			// Not a subcommand, so prepend "raw"
@@ -47,4 +47,5 @@ func init() {
 	rootCmd.AddCommand(rawCmd)
 	rootCmd.AddCommand(asciiCmd)
 	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(vectorsCmd)
 }
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/vrypan/ring30mix/internal/vectors"
+)
+
+var vectorsCheck string
+
+var vectorsCmd = &cobra.Command{
+	Use:   "vectors",
+	Short: "Emit or check the golden test vectors",
+	Long: `Emit or check the golden test vectors.
+
+The vectors pin the values rand.NewVersion(seed, v) returns from Uint64,
+Read, Int, Intn, Float64 and NormFloat64 for every stream version, one
+JSON object per line, and "default" vectors that pin rand.New(seed) to
+the version it follows. The copy in rand/testdata/vectors.jsonl is checked
+by go test; a change to the generator that alters any value needs a new
+stream version rather than new vectors.
+
+Examples:
+  # Regenerate the vectors (only when adding a stream version or moving
+  # LatestVersion)
+  r30r2 vectors > rand/testdata/vectors.jsonl
+
+  # Check that this build reproduces a vectors file
+  r30r2 vectors --check rand/testdata/vectors.jsonl`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if vectorsCheck == "" {
+			if err := vectors.Write(os.Stdout, vectors.Generate()); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		f, err := os.Open(vectorsCheck)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		vs, err := vectors.Parse(f)
+		f.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		failed := 0
+		if err := vectors.Complete(vs); err != nil {
+			fmt.Fprintf(os.Stderr, "FAIL: %v\n", err)
+			failed++
+		}
+		for _, vec := range vs {
+			if err := vectors.Check(vec); err != nil {
+				fmt.Fprintf(os.Stderr, "FAIL: %v\n", err)
+				failed++
+			}
+		}
+		if failed > 0 {
+			fmt.Fprintf(os.Stderr, "%d of %d vectors failed\n", failed, len(vs))
+			os.Exit(1)
+		}
+		fmt.Printf("ok: %d vectors match\n", len(vs))
+	},
+}
+
+func init() {
+	vectorsCmd.Flags().StringVar(&vectorsCheck, "check", "", "Check this vectors file instead of emitting vectors")
+}
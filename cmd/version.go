@@ -17,7 +17,7 @@ var versionCmd = &cobra.Command{
 	Long:  `Print the version number and build information for r30r2.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Printf("r30r2 version %s\n", Version)
-		fmt.Printf("Random Number Generator using Rule 30 Cellular Automaton (Radius-2)\n")
+		fmt.Printf("Random Number Generator using Rule 30 Cellular Automaton (Radius-1)\n")
 		fmt.Printf("\n")
 		fmt.Printf("Repository: %s\n", GitRepo)
 		fmt.Printf("Statistical Quality: 160/160 BigCrush tests passed\n")
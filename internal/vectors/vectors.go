@@ -0,0 +1,254 @@
+// Package vectors generates and checks the golden test vectors that pin
+// the output of rand.NewVersion(seed, v) for every stream version, and
+// which version rand.New(seed) follows. The vectors live in
+// rand/testdata/vectors.jsonl, one JSON object per line;
+// `ring30mix vectors` writes and checks them, and the golden test in the
+// rand package checks them on every build.
+package vectors
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+
+	"github.com/vrypan/ring30mix/rand"
+)
+
+// Vector holds the first values of each covered method for one version
+// and seed. Every field starts from a fresh NewVersion(seed, Version), so
+// a failure points at one method.
+//
+// A Default vector is generated from New(seed) and pins the default
+// stream: it must match NewVersion(seed, Version) too, and Version must be
+// LatestVersion, so moving LatestVersion fails until the default vectors
+// are regenerated.
+type Vector struct {
+	Version rand.Version `json:"version"`
+	Default bool         `json:"default,omitempty"`
+	Seed    uint64       `json:"seed"`
+	Uint64  []uint64     `json:"uint64"`
+	// Read holds consecutive Reads of odd lengths from one generator,
+	// hex encoded, so the bytes kept between Reads are covered too
+	Read []string `json:"read"`
+	// Int holds Int63 values. Int returns the same value on 64-bit
+	// platforms and its low 31 bits on 32-bit ones.
+	Int         []int64    `json:"int"`
+	Intn        [][2]int64 `json:"intn"` // bound, value
+	Float64     []float64  `json:"float64"`
+	NormFloat64 []float64  `json:"norm_float64"`
+}
+
+// Values per method
+const count = 16
+
+// readLengths are the byte counts of the Read calls
+var readLengths = []int{1, 3, 5, 7, 9, 13, 31, 33, 63, 65}
+
+// intnBounds are the arguments of the Intn calls; all fit in 32 bits
+var intnBounds = []int64{1, 2, 3, 5, 6, 7, 10, 100, 1000, 1<<20 + 1, 1<<30 + 3, 1<<31 - 1}
+
+// Seeds returns the seeds the vectors cover: edge cases, then a fixed
+// pseudo-random sequence
+func Seeds() []uint64 {
+	seeds := []uint64{0, 1, 2, 3, 42, 1234, 12345, 1 << 32, 1 << 63, math.MaxUint64}
+	x := uint64(0x5eed)
+	for len(seeds) < 32 {
+		// SplitMix64
+		x += 0x9e3779b97f4a7c15
+		z := x
+		z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+		z = (z ^ z>>27) * 0x94d049bb133111eb
+		seeds = append(seeds, z^z>>31)
+	}
+	return seeds
+}
+
+// Generate returns the vectors for every version and seed, then the
+// default vectors
+func Generate() []Vector {
+	var vs []Vector
+	for v := rand.V1; v <= rand.LatestVersion; v++ {
+		for _, seed := range Seeds() {
+			vs = append(vs, generate(Vector{Version: v, Seed: seed}))
+		}
+	}
+	for _, seed := range Seeds() {
+		vs = append(vs, generate(Vector{Version: rand.LatestVersion, Default: true, Seed: seed}))
+	}
+	return vs
+}
+
+// newRNG returns the generator a field of vec starts from
+func newRNG(vec Vector) *rand.RNG {
+	if vec.Default {
+		return rand.New(vec.Seed)
+	}
+	return rand.NewVersion(vec.Seed, vec.Version)
+}
+
+// generate fills in the values of vec
+func generate(vec Vector) Vector {
+	rng := newRNG(vec)
+	for range count {
+		vec.Uint64 = append(vec.Uint64, rng.Uint64())
+	}
+	rng = newRNG(vec)
+	for _, n := range readLengths {
+		buf := make([]byte, n)
+		rng.Read(buf)
+		vec.Read = append(vec.Read, hex.EncodeToString(buf))
+	}
+	// Int63 rather than Int, so the file is the same on 32-bit hosts
+	rng = newRNG(vec)
+	for range count {
+		vec.Int = append(vec.Int, rng.Int63())
+	}
+	rng = newRNG(vec)
+	for _, n := range intnBounds {
+		vec.Intn = append(vec.Intn, [2]int64{n, int64(rng.Intn(int(n)))})
+	}
+	rng = newRNG(vec)
+	for range count {
+		vec.Float64 = append(vec.Float64, rng.Float64())
+	}
+	rng = newRNG(vec)
+	for range count {
+		vec.NormFloat64 = append(vec.NormFloat64, rng.NormFloat64())
+	}
+	return vec
+}
+
+// Write writes vectors one per line
+func Write(w io.Writer, vs []Vector) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for _, v := range vs {
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// Parse reads vectors written by Write
+func Parse(r io.Reader) ([]Vector, error) {
+	var vs []Vector
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	for {
+		var v Vector
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			return vs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("vectors: line %d: %w", len(vs)+1, err)
+		}
+		vs = append(vs, v)
+	}
+}
+
+// Check reports the first value in vec that this build doesn't reproduce
+func Check(vec Vector) error {
+	if vec.Version < rand.V1 || vec.Version > rand.LatestVersion {
+		return fmt.Errorf("vectors: unknown version %d", uint8(vec.Version))
+	}
+	if !vec.Default {
+		return check(vec, fmt.Sprintf("%v seed %d", vec.Version, vec.Seed))
+	}
+	if vec.Version != rand.LatestVersion {
+		return fmt.Errorf("vectors: default vectors pin %v but New follows %v; regenerate them",
+			vec.Version, rand.LatestVersion)
+	}
+	if err := check(vec, fmt.Sprintf("default seed %d", vec.Seed)); err != nil {
+		return err
+	}
+	named := vec
+	named.Default = false
+	return check(named, fmt.Sprintf("default as %v seed %d", vec.Version, vec.Seed))
+}
+
+// check compares each field of vec against this build, naming the
+// vector as where in errors
+func check(vec Vector, where string) error {
+	rng := newRNG(vec)
+	for i, want := range vec.Uint64 {
+		if got := rng.Uint64(); got != want {
+			return fmt.Errorf("%s: Uint64 #%d = %#x, want %#x", where, i, got, want)
+		}
+	}
+	rng = newRNG(vec)
+	for i, s := range vec.Read {
+		want, err := hex.DecodeString(s)
+		if err != nil {
+			return fmt.Errorf("%s: Read #%d: %w", where, i, err)
+		}
+		got := make([]byte, len(want))
+		rng.Read(got)
+		if !slices.Equal(got, want) {
+			return fmt.Errorf("%s: Read #%d of %d bytes = %x, want %x", where, i, len(want), got, want)
+		}
+	}
+	rng = newRNG(vec)
+	for i, v := range vec.Int {
+		// int(v) keeps the low 32 bits on 32-bit platforms
+		if got, want := rng.Int(), int(v)&math.MaxInt; got != want {
+			return fmt.Errorf("%s: Int #%d = %d, want %d", where, i, got, want)
+		}
+	}
+	rng = newRNG(vec)
+	for i, nv := range vec.Intn {
+		if got := int64(rng.Intn(int(nv[0]))); got != nv[1] {
+			return fmt.Errorf("%s: Intn(%d) #%d = %d, want %d", where, nv[0], i, got, nv[1])
+		}
+	}
+	rng = newRNG(vec)
+	for i, want := range vec.Float64 {
+		if got := rng.Float64(); got != want {
+			return fmt.Errorf("%s: Float64 #%d = %v, want %v", where, i, got, want)
+		}
+	}
+	rng = newRNG(vec)
+	for i, want := range vec.NormFloat64 {
+		if got := rng.NormFloat64(); got != want {
+			return fmt.Errorf("%s: NormFloat64 #%d = %v, want %v", where, i, got, want)
+		}
+	}
+	return nil
+}
+
+// Complete reports the first version and seed that vs has no vector for,
+// so a new version can't ship without vectors, and the first seed that
+// has no default vector
+func Complete(vs []Vector) error {
+	type key struct {
+		v    rand.Version
+		seed uint64
+	}
+	have := make(map[key]bool)
+	hasDefault := make(map[uint64]bool)
+	for _, vec := range vs {
+		if vec.Default {
+			hasDefault[vec.Seed] = true
+		} else {
+			have[key{vec.Version, vec.Seed}] = true
+		}
+	}
+	for v := rand.V1; v <= rand.LatestVersion; v++ {
+		for _, seed := range Seeds() {
+			if !have[key{v, seed}] {
+				return fmt.Errorf("vectors: no vector for %v seed %d", v, seed)
+			}
+		}
+	}
+	for _, seed := range Seeds() {
+		if !hasDefault[seed] {
+			return fmt.Errorf("vectors: no default vector for seed %d", seed)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package rand
+
+// This file contains fast-forwarding. Rule 30 has no jump-ahead formula,
+// so skipping is still linear in n, but it only runs step() once per four
+// discarded outputs and never calls mix().
+
+// Advance moves the generator forward as if Uint64 had been called n times
+func (r *RNG) Advance(n uint64) {
+	avail := uint64(4 - r.pos)
+	if n <= avail {
+		r.pos += int(n)
+		return
+	}
+	n -= avail
+
+	// Every started group of 4 outputs needs one step; the last may be partial
+	steps := (n + 3) / 4
+	for i := uint64(0); i < steps; i++ {
+		r.step()
+	}
+	r.pos = int(n - 4*(steps-1))
+}
+
+// SkipBytes moves the generator forward as if Read had returned n bytes
+func (r *RNG) SkipBytes(n uint64) {
+	// Bytes left over from the previous Read come first
+	if used := min(n, uint64(r.ntail)); used > 0 {
+		r.tail >>= 8 * used
+		r.ntail -= int(used)
+		n -= used
+	}
+	r.Advance(n / 8)
+
+	// A partial word leaves its unused bytes for the next Read
+	if rem := n % 8; rem > 0 {
+		r.tail, r.ntail = r.Uint64()>>(8*rem), int(8-rem)
+	}
+}
@@ -0,0 +1,48 @@
+package rand
+
+import "testing"
+
+func TestAdvanceMatchesUint64(t *testing.T) {
+	for start := 0; start < 5; start++ {
+		for n := uint64(0); n < 41; n++ {
+			a, b := New(123), New(123)
+			for i := 0; i < start; i++ {
+				a.Uint64()
+				b.Uint64()
+			}
+			a.Advance(n)
+			for i := uint64(0); i < n; i++ {
+				b.Uint64()
+			}
+			if a.state != b.state || a.pos != b.pos {
+				t.Fatalf("start %d, Advance(%d): state %v/%d, want %v/%d",
+					start, n, a.state, a.pos, b.state, b.pos)
+			}
+			sameStream(t, a, b, 9)
+		}
+	}
+}
+
+func TestAdvanceLarge(t *testing.T) {
+	const n = 1_000_003
+	a, b := New(5), New(5)
+	a.Advance(n)
+	for i := 0; i < n; i++ {
+		b.Uint64()
+	}
+	sameStream(t, a, b, 16)
+}
+
+func TestSkipBytesMatchesRead(t *testing.T) {
+	for _, n := range []uint64{0, 1, 7, 8, 9, 31, 32, 33, 1000} {
+		a, b := New(42), New(42)
+		a.SkipBytes(n)
+		b.Read(make([]byte, n))
+		buf1, buf2 := make([]byte, 40), make([]byte, 40)
+		a.Read(buf1)
+		b.Read(buf2)
+		if string(buf1) != string(buf2) {
+			t.Fatalf("SkipBytes(%d) diverges from Read", n)
+		}
+	}
+}
@@ -74,23 +74,45 @@ func (r *RNG) Intn(n int) int {
 
 // Float64 returns a random float64 in [0.0, 1.0)
 func (r *RNG) Float64() float64 {
-	// Use 53 bits of precision (same as math/rand)
-	return float64(r.Int63()>>11) / (1 << 52)
+	return wordFloat64(r.Uint64())
 }
 
 // Float32 returns a random float32 in [0.0, 1.0)
 func (r *RNG) Float32() float32 {
-	// Use 24 bits of precision
-	return float32(r.Int31()>>7) / (1 << 24)
+	return wordFloat32(r.Uint64())
+}
+
+// Float64Open returns a random float64 in the open interval (0.0, 1.0)
+// The values are the midpoints of 2^52 equal cells, all exact in a
+// float64, so both log(u) and log(1-u) are finite and u and 1-u have
+// exactly the same distribution. (With 53 bits the top midpoint would
+// round to 1.)
+func (r *RNG) Float64Open() float64 {
+	return (float64(r.Uint64()>>12) + 0.5) / (1 << 52)
+}
+
+// wordFloat64 converts a Uint64 output to the Float64 it stands for
+func wordFloat64(u uint64) float64 {
+	// 52 bits of the Int63 value (same as math/rand)
+	return float64(u&(1<<63-1)>>11) / (1 << 52)
+}
+
+// wordFloat32 converts a Uint64 output to the Float32 it stands for
+func wordFloat32(u uint64) float32 {
+	// 24 bits of the Int31 value, which comes from the low 32 bits
+	return float32(uint32(u)>>8) / (1 << 24)
 }
 
 // NormFloat64 returns a normally distributed float64 with mean 0 and stddev 1
-// Uses the Box-Muller transform
+// Uses a ziggurat sampler, or the Marsaglia polar method under V1
 func (r *RNG) NormFloat64() float64 {
+	if r.version != V1 {
+		return r.normZiggurat()
+	}
 	for {
 		u := 2*r.Float64() - 1
 		v := 2*r.Float64() - 1
-		s := u*u + v*v
+		s := float64(u*u) + float64(v*v) // no FMA, see V1
 		if s < 1 && s != 0 {
 			return u * math.Sqrt(-2*math.Log(s)/s)
 		}
@@ -98,7 +120,11 @@ func (r *RNG) NormFloat64() float64 {
 }
 
 // ExpFloat64 returns an exponentially distributed float64 with rate 1
+// Uses a ziggurat sampler, or -log(u) under V1
 func (r *RNG) ExpFloat64() float64 {
+	if r.version != V1 {
+		return r.expZiggurat()
+	}
 	for {
 		u := r.Float64()
 		if u > 0 {
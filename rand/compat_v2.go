@@ -0,0 +1,155 @@
+package rand
+
+import "math/bits"
+
+// This file contains math/rand/v2 compatibility methods. Bounded integers
+// use Lemire's nearly-divisionless method: one multiplication per call, and
+// a division only in the rare case the fast path cannot rule out bias.
+
+// Int32 returns a non-negative random int32 (0 to 2^31-1)
+func (r *RNG) Int32() int32 {
+	return int32(r.Uint64() >> 33)
+}
+
+// Int64 returns a non-negative random int64 (0 to 2^63-1)
+func (r *RNG) Int64() int64 {
+	return int64(r.Uint64() >> 1)
+}
+
+// Uint returns a random uint
+func (r *RNG) Uint() uint {
+	return uint(r.Uint64())
+}
+
+// uint64n returns a random uint64 in [0, n)
+// n must be non-zero
+func (r *RNG) uint64n(n uint64) uint64 {
+	if n&(n-1) == 0 { // n is power of two
+		return r.Uint64() & (n - 1)
+	}
+	hi, lo := bits.Mul64(r.Uint64(), n)
+	if lo < n {
+		thresh := -n % n
+		for lo < thresh {
+			hi, lo = bits.Mul64(r.Uint64(), n)
+		}
+	}
+	return hi
+}
+
+// uint32n returns a random uint32 in [0, n)
+// n must be non-zero
+func (r *RNG) uint32n(n uint32) uint32 {
+	if n&(n-1) == 0 { // n is power of two
+		return uint32(r.Uint64()>>32) & (n - 1)
+	}
+	m := (r.Uint64() >> 32) * uint64(n)
+	if uint32(m) < n {
+		thresh := -n % n
+		for uint32(m) < thresh {
+			m = (r.Uint64() >> 32) * uint64(n)
+		}
+	}
+	return uint32(m >> 32)
+}
+
+// Uint64N returns a random uint64 in [0, n)
+// Panics if n == 0
+func (r *RNG) Uint64N(n uint64) uint64 {
+	if n == 0 {
+		panic("invalid argument to Uint64N")
+	}
+	return r.uint64n(n)
+}
+
+// Uint32N returns a random uint32 in [0, n)
+// Panics if n == 0
+func (r *RNG) Uint32N(n uint32) uint32 {
+	if n == 0 {
+		panic("invalid argument to Uint32N")
+	}
+	return r.uint32n(n)
+}
+
+// UintN returns a random uint in [0, n)
+// Panics if n == 0
+func (r *RNG) UintN(n uint) uint {
+	if n == 0 {
+		panic("invalid argument to UintN")
+	}
+	return uint(r.uint64n(uint64(n)))
+}
+
+// Int64N returns a random int64 in [0, n)
+// Panics if n <= 0
+func (r *RNG) Int64N(n int64) int64 {
+	if n <= 0 {
+		panic("invalid argument to Int64N")
+	}
+	return int64(r.uint64n(uint64(n)))
+}
+
+// Int32N returns a random int32 in [0, n)
+// Panics if n <= 0
+func (r *RNG) Int32N(n int32) int32 {
+	if n <= 0 {
+		panic("invalid argument to Int32N")
+	}
+	return int32(r.uint32n(uint32(n)))
+}
+
+// IntN returns a random int in [0, n)
+// Panics if n <= 0
+func (r *RNG) IntN(n int) int {
+	if n <= 0 {
+		panic("invalid argument to IntN")
+	}
+	return int(r.uint64n(uint64(n)))
+}
+
+// Perm returns a random permutation of the integers [0, n)
+// Panics if n < 0
+func (r *RNG) Perm(n int) []int {
+	if n < 0 {
+		panic("invalid argument to Perm")
+	}
+	p := make([]int, n)
+	for i := range p {
+		p[i] = i
+	}
+	r.Shuffle(len(p), func(i, j int) { p[i], p[j] = p[j], p[i] })
+	return p
+}
+
+// Shuffle pseudo-randomizes the order of n elements using Fisher-Yates
+// swap swaps the elements with indexes i and j
+// Panics if n < 0
+func (r *RNG) Shuffle(n int, swap func(i, j int)) {
+	if n < 0 {
+		panic("invalid argument to Shuffle")
+	}
+	for i := n - 1; i > 0; i-- {
+		j := int(r.uint64n(uint64(i + 1)))
+		swap(i, j)
+	}
+}
+
+// intType is the set of integer types accepted by N
+type intType interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// N returns a random value in [0, n) drawn from r
+// It is the counterpart of math/rand/v2.N and works with any integer type,
+// including time.Duration:
+//
+//	delay := rand.N(rng, 100*time.Millisecond)
+//
+// Panics if n <= 0
+func N[Int intType](r *RNG, n Int) Int {
+	if n <= 0 {
+		panic("invalid argument to N")
+	}
+	return Int(r.uint64n(uint64(n)))
+}
@@ -0,0 +1,207 @@
+package rand
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+// chiSquare255 is the p = 0.001 critical value of chi-square with 255
+// degrees of freedom, for tests over 256 equiprobable bins
+const chiSquare255 = 330.5
+
+// chiSquare returns the chi-square statistic of counts against a uniform
+// expectation
+func chiSquare(counts []int, total int) float64 {
+	expected := float64(total) / float64(len(counts))
+	var chi2 float64
+	for _, c := range counts {
+		d := float64(c) - expected
+		chi2 += d * d / expected
+	}
+	return chi2
+}
+
+// chiSquareBytes reads n bytes from r and returns the chi-square statistic
+// of their values, to compare against chiSquare255
+func chiSquareBytes(t *testing.T, r io.Reader, n int) float64 {
+	t.Helper()
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		t.Fatal(err)
+	}
+	hist := make([]int, 256)
+	for _, b := range buf {
+		hist[b]++
+	}
+	return chiSquare(hist, n)
+}
+
+func TestBoundedRanges(t *testing.T) {
+	rng := New(2024)
+	for _, n := range []uint64{1, 2, 3, 7, 10, 1<<32 + 1, 1<<63 + 1, ^uint64(0)} {
+		for i := 0; i < 1000; i++ {
+			if v := rng.Uint64N(n); v >= n {
+				t.Fatalf("Uint64N(%d) = %d", n, v)
+			}
+		}
+	}
+	for _, n := range []uint32{1, 3, 1000, 1<<31 + 1, ^uint32(0)} {
+		for i := 0; i < 1000; i++ {
+			if v := rng.Uint32N(n); v >= n {
+				t.Fatalf("Uint32N(%d) = %d", n, v)
+			}
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		if v := rng.IntN(6); v < 0 || v >= 6 {
+			t.Fatalf("IntN(6) = %d", v)
+		}
+		if v := rng.Int32N(6); v < 0 || v >= 6 {
+			t.Fatalf("Int32N(6) = %d", v)
+		}
+		if v := rng.Int64N(6); v < 0 || v >= 6 {
+			t.Fatalf("Int64N(6) = %d", v)
+		}
+		if v := rng.UintN(6); v >= 6 {
+			t.Fatalf("UintN(6) = %d", v)
+		}
+		if v := rng.Int32(); v < 0 {
+			t.Fatalf("Int32() = %d", v)
+		}
+		if v := rng.Int64(); v < 0 {
+			t.Fatalf("Int64() = %d", v)
+		}
+	}
+}
+
+func TestBoundedUniform(t *testing.T) {
+	const n, samples = 7, 70000
+	rng := New(7)
+	counts64 := make([]int, n)
+	counts32 := make([]int, n)
+	for i := 0; i < samples; i++ {
+		counts64[rng.Uint64N(n)]++
+		counts32[rng.Uint32N(n)]++
+	}
+	// 6 degrees of freedom: p = 0.001 at 22.46
+	if chi2 := chiSquare(counts64, samples); chi2 > 22.46 {
+		t.Errorf("Uint64N(%d) chi-square = %.2f, counts %v", n, chi2, counts64)
+	}
+	if chi2 := chiSquare(counts32, samples); chi2 > 22.46 {
+		t.Errorf("Uint32N(%d) chi-square = %.2f, counts %v", n, chi2, counts32)
+	}
+}
+
+func TestBoundedLargeNUnbiased(t *testing.T) {
+	// With n = 3·2^62 a modulo reduction would put half of the mass in the
+	// bottom third; Lemire's method keeps every third equally likely.
+	const n = 3 << 62
+	const samples = 30000
+	rng := New(99)
+	counts := make([]int, 3)
+	for i := 0; i < samples; i++ {
+		counts[rng.Uint64N(n)>>62]++
+	}
+	// 2 degrees of freedom: p = 0.001 at 13.82
+	if chi2 := chiSquare(counts, samples); chi2 > 13.82 {
+		t.Errorf("Uint64N(3<<62) thirds chi-square = %.2f, counts %v", chi2, counts)
+	}
+}
+
+func TestBoundedPanics(t *testing.T) {
+	rng := New(1)
+	for name, f := range map[string]func(){
+		"IntN(0)":     func() { rng.IntN(0) },
+		"Int32N(-1)":  func() { rng.Int32N(-1) },
+		"Int64N(0)":   func() { rng.Int64N(0) },
+		"UintN(0)":    func() { rng.UintN(0) },
+		"Uint32N(0)":  func() { rng.Uint32N(0) },
+		"Uint64N(0)":  func() { rng.Uint64N(0) },
+		"N(0)":        func() { N(rng, time.Duration(0)) },
+		"Perm(-1)":    func() { rng.Perm(-1) },
+		"Shuffle(-1)": func() { rng.Shuffle(-1, func(i, j int) {}) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestN(t *testing.T) {
+	rng := New(5)
+	for i := 0; i < 1000; i++ {
+		if d := N(rng, 100*time.Millisecond); d < 0 || d >= 100*time.Millisecond {
+			t.Fatalf("N(100ms) = %v", d)
+		}
+		if v := N(rng, uint8(200)); v >= 200 {
+			t.Fatalf("N(uint8(200)) = %d", v)
+		}
+	}
+}
+
+func TestPerm(t *testing.T) {
+	rng := New(11)
+	for _, n := range []int{0, 1, 2, 10, 1000} {
+		p := rng.Perm(n)
+		if len(p) != n {
+			t.Fatalf("Perm(%d) has length %d", n, len(p))
+		}
+		seen := make([]bool, n)
+		for _, v := range p {
+			if v < 0 || v >= n || seen[v] {
+				t.Fatalf("Perm(%d) is not a permutation: %v", n, p)
+			}
+			seen[v] = true
+		}
+	}
+}
+
+func TestShuffleUniform(t *testing.T) {
+	// All 6 orderings of 3 elements should be equally likely
+	const samples = 60000
+	rng := New(3)
+	counts := make([]int, 6)
+	index := map[[3]int]int{}
+	for i := 0; i < samples; i++ {
+		a := [3]int{0, 1, 2}
+		rng.Shuffle(3, func(i, j int) { a[i], a[j] = a[j], a[i] })
+		k, ok := index[a]
+		if !ok {
+			k = len(index)
+			index[a] = k
+		}
+		counts[k]++
+	}
+	if len(index) != 6 {
+		t.Fatalf("Shuffle produced %d distinct orderings, want 6", len(index))
+	}
+	// 5 degrees of freedom: p = 0.001 at 20.52
+	if chi2 := chiSquare(counts, samples); chi2 > 20.52 {
+		t.Errorf("Shuffle chi-square = %.2f, counts %v", chi2, counts)
+	}
+}
+
+func TestFloat64Open(t *testing.T) {
+	// One word per value, mapped to the midpoint of its cell
+	a, b := New(8), New(8)
+	for i := 0; i < 1000; i++ {
+		u, w := a.Float64Open(), b.Uint64()
+		if u <= 0 || u >= 1 {
+			t.Fatalf("Float64Open = %v, outside (0, 1)", u)
+		}
+		if want := (float64(w>>12) + 0.5) / (1 << 52); u != want {
+			t.Fatalf("Float64Open = %v, want %v", u, want)
+		}
+	}
+	// The extreme cells are symmetric about 1/2
+	lo, hi := 0.5/(1<<52), (float64(1<<52-1)+0.5)/(1<<52)
+	if lo+hi != 1 || hi >= 1 {
+		t.Fatalf("extreme values %v and %v", lo, hi)
+	}
+}
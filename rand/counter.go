@@ -0,0 +1,120 @@
+package rand
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"runtime"
+	"sync"
+)
+
+// This file contains the counter-based generator. Where RNG walks one
+// ring forward, CounterRNG computes any block directly from (key, counter),
+// like Philox or Threefry: the counter is spread over a copy of the key,
+// the ring runs a fixed number of Rule 30 steps, and each word goes
+// through mix().
+
+// DefaultCounterRounds is the number of Rule 30 steps per block
+// Flipping any counter bit reaches the noise floor of the avalanche test
+// in counter_test.go after 16-20 rounds; 32 leaves a safety margin.
+const DefaultCounterRounds = 32
+
+// counterMul spreads the counter differently into each ring word, so the
+// Rule 30 steps start from a difference in every word instead of one
+var counterMul = [4]uint64{
+	0x9e3779b97f4a7c15,
+	0xbf58476d1ce4e5b9,
+	0x94d049bb133111eb,
+	0xd6e8feb86659fd93,
+}
+
+// fillMinBlocks is the least work worth handing to a Fill goroutine
+const fillMinBlocks = 4096
+
+// CounterRNG is a stateless, random-access generator
+// Block is a pure function of the key and counter, so a CounterRNG is safe
+// for concurrent use and any part of the stream can be computed in any
+// order.
+type CounterRNG struct {
+	key    [4]uint64
+	rounds int
+}
+
+// NewCounter creates a counter-based generator with DefaultCounterRounds
+func NewCounter(key [32]byte) *CounterRNG {
+	return NewCounterRounds(key, DefaultCounterRounds)
+}
+
+// NewCounterRounds creates a counter-based generator with a custom number
+// of Rule 30 steps per block, for quality research
+// Panics if rounds < 0
+func NewCounterRounds(key [32]byte, rounds int) *CounterRNG {
+	if rounds < 0 {
+		panic("invalid argument to NewCounterRounds")
+	}
+	var words [4]uint64
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint64(key[8*i:])
+	}
+	return &CounterRNG{key: expand(words[:], tagCounter), rounds: rounds}
+}
+
+// At returns block counter of the stream for key
+// It is shorthand for NewCounter(key).Block(counter); reuse a CounterRNG
+// when computing many blocks for the same key.
+func At(key [32]byte, counter uint64) [4]uint64 {
+	return NewCounter(key).Block(counter)
+}
+
+// Rounds returns the number of Rule 30 steps per block
+func (c *CounterRNG) Rounds() int {
+	return c.rounds
+}
+
+// Block returns the four words for counter
+func (c *CounterRNG) Block(counter uint64) [4]uint64 {
+	var g RNG
+	for i := range g.state {
+		g.state[i] = c.key[i] ^ bits.RotateLeft64(counter*counterMul[i], 16*i)
+	}
+	for i := 0; i < c.rounds; i++ {
+		g.step()
+	}
+	return [4]uint64{mix(g.state[0]), mix(g.state[1]), mix(g.state[2]), mix(g.state[3])}
+}
+
+// Fill fills dst with consecutive blocks starting at counter
+// dst[4i:4i+4] holds Block(counter+i); a trailing partial block is
+// truncated. Large fills are split across GOMAXPROCS goroutines, which is
+// possible because every block is independent.
+func (c *CounterRNG) Fill(dst []uint64, counter uint64) {
+	blocks := (len(dst) + 3) / 4
+	workers := min(runtime.GOMAXPROCS(0), blocks/fillMinBlocks)
+	if workers <= 1 {
+		c.fill(dst, counter)
+		return
+	}
+
+	per := (blocks + workers - 1) / workers
+	var wg sync.WaitGroup
+	for lo := 0; lo < blocks; lo += per {
+		hi := min(lo+per, blocks)
+		wg.Go(func() {
+			c.fill(dst[4*lo:min(4*hi, len(dst))], counter+uint64(lo))
+		})
+	}
+	wg.Wait()
+}
+
+// fill is the sequential body of Fill
+func (c *CounterRNG) fill(dst []uint64, counter uint64) {
+	for len(dst) >= 4 {
+		b := c.Block(counter)
+		copy(dst, b[:])
+		dst = dst[4:]
+		counter++
+	}
+	if len(dst) > 0 {
+		b := c.Block(counter)
+		copy(dst, b[:])
+	}
+}
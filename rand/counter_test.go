@@ -0,0 +1,105 @@
+package rand
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func testKey(b byte) [32]byte {
+	var key [32]byte
+	for i := range key {
+		key[i] = b + byte(i)
+	}
+	return key
+}
+
+func TestCounterDeterministic(t *testing.T) {
+	c := NewCounter(testKey(1))
+	if c.Rounds() != DefaultCounterRounds {
+		t.Fatalf("Rounds() = %d, want %d", c.Rounds(), DefaultCounterRounds)
+	}
+	for _, ctr := range []uint64{0, 1, 2, 1 << 40, ^uint64(0)} {
+		x, y := c.Block(ctr), At(testKey(1), ctr)
+		if x != y {
+			t.Fatalf("Block(%d) = %x, At = %x", ctr, x, y)
+		}
+		if x == NewCounter(testKey(2)).Block(ctr) {
+			t.Fatalf("different keys give the same Block(%d)", ctr)
+		}
+	}
+	if c.Block(0) == c.Block(1) {
+		t.Fatal("adjacent counters give the same block")
+	}
+}
+
+func TestCounterFillMatchesBlock(t *testing.T) {
+	c := NewCounter(testKey(3))
+	// Cover a partial last block and a fill large enough to go parallel
+	for _, n := range []int{0, 1, 5, 8, 4*fillMinBlocks*3 + 2} {
+		dst := make([]uint64, n)
+		c.Fill(dst, 100)
+		for i := range dst {
+			if want := c.Block(100 + uint64(i/4))[i%4]; dst[i] != want {
+				t.Fatalf("Fill(%d)[%d] = %#x, want %#x", n, i, dst[i], want)
+			}
+		}
+	}
+}
+
+// counterQuality runs two checks on a counter generator and returns the
+// worst avalanche deviation and a byte chi-square statistic.
+//
+// Avalanche: for each counter bit, flipping it should flip each of the 256
+// output bits with probability 1/2. Chi-square: bytes from sequential
+// counters should be uniform. These are the properties the Rule 30 rounds
+// have to provide, since the counter enters the ring without a mixer.
+func counterQuality(t *testing.T, c *CounterRNG) (worst, chi2 float64) {
+	const trials = 1000
+	for b := 0; b < 64; b++ {
+		var flips [256]int
+		for tr := uint64(0); tr < trials; tr++ {
+			ctr := tr * 0x9e3779b97f4a7c15
+			x, y := c.Block(ctr), c.Block(ctr^1<<b)
+			for w := range x {
+				d := x[w] ^ y[w]
+				for k := 0; k < 64; k++ {
+					flips[64*w+k] += int(d >> k & 1)
+				}
+			}
+		}
+		for _, f := range flips {
+			worst = math.Max(worst, math.Abs(float64(f)/trials-0.5))
+		}
+	}
+
+	words := make([]uint64, 4*20000)
+	c.Fill(words, 0)
+	var buf []byte
+	for _, w := range words {
+		buf = binary.LittleEndian.AppendUint64(buf, w)
+	}
+	return worst, chiSquareBytes(t, bytes.NewReader(buf), len(buf))
+}
+
+func TestCounterRoundsQuality(t *testing.T) {
+	// 64 counter bits × 256 output bits of a binomial(1000, 1/2) proportion
+	// put the noise floor for the worst deviation near 0.06; 0.08 is 5 sigma
+	const maxDeviation = 0.08
+	rounds := []int{4, 8, 12, 16, 20, 24, DefaultCounterRounds}
+	if testing.Short() {
+		rounds = []int{4, DefaultCounterRounds}
+	}
+	for _, r := range rounds {
+		worst, chi2 := counterQuality(t, NewCounterRounds(testKey(9), r))
+		pass := worst <= maxDeviation && chi2 <= chiSquare255
+		t.Logf("rounds %2d: worst avalanche deviation %.3f, byte chi-square %6.1f, pass %v", r, worst, chi2, pass)
+		switch {
+		case r == DefaultCounterRounds && !pass:
+			t.Errorf("default rounds %d fail the quality check", r)
+		case r <= 8 && pass:
+			t.Errorf("%d rounds unexpectedly pass; the check has lost its power", r)
+		}
+	}
+}
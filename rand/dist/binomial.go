@@ -0,0 +1,262 @@
+package dist
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/vrypan/ring30mix/rand"
+)
+
+// This file contains the binomial and multinomial distributions.
+
+// binomialBTPEMin is the smallest n*min(p, 1-p) sampled with BTPE; below
+// it inversion needs O(n*p) steps, which is cheaper than BTPE's setup
+const binomialBTPEMin = 30
+
+// Binomial is the number of successes in n Bernoulli(p) trials
+// Sampling is O(1) expected time: inversion when n*min(p, 1-p) < 30, and
+// otherwise BTPE from V. Kachitvichyanukul and B. W. Schmeiser, "Binomial
+// Random Variate Generation", Communications of the ACM 31(2), 1988. Both
+// sample with min(p, 1-p) and reflect the result.
+type Binomial struct {
+	n       int64
+	p, r, q float64 // p as given, r = min(p, 1-p), q = 1-r
+	// Inversion constants
+	qn, bound float64
+	// BTPE constants
+	m, p1, xm, xl, xr, c, laml, lamr, p2, p3, p4, nrq float64
+}
+
+var _ Discrete = Binomial{}
+
+// NewBinomial returns a binomial distribution
+// n must be >= 0 and p in [0, 1].
+func NewBinomial(n int64, p float64) (Binomial, error) {
+	if n < 0 {
+		return Binomial{}, fmt.Errorf("%w: binomial n = %d, want n >= 0", ErrInvalidParameter, n)
+	}
+	if err := checkProbability("binomial p", p); err != nil {
+		return Binomial{}, err
+	}
+	return newBinomial(n, p), nil
+}
+
+// newBinomial builds a binomial distribution from validated parameters
+func newBinomial(n int64, p float64) Binomial {
+	d := Binomial{n: n, p: p, r: min(p, 1-p)}
+	d.q = 1 - d.r
+	fn := float64(n)
+	np := fn * d.r
+	if np < binomialBTPEMin {
+		d.qn = math.Exp(fn * math.Log1p(-d.r))
+		d.bound = min(fn, np+10*math.Sqrt(np*d.q+1))
+		return d
+	}
+	fm := np + d.r
+	d.m = math.Floor(fm)
+	d.nrq = np * d.q
+	d.p1 = math.Floor(2.195*math.Sqrt(d.nrq)-4.6*d.q) + 0.5
+	d.xm = d.m + 0.5
+	d.xl = d.xm - d.p1
+	d.xr = d.xm + d.p1
+	d.c = 0.134 + 20.5/(15.3+d.m)
+	a := (fm - d.xl) / (fm - d.xl*d.r)
+	d.laml = a * (1 + a/2)
+	a = (d.xr - fm) / (d.xr * d.q)
+	d.lamr = a * (1 + a/2)
+	d.p2 = d.p1 * (1 + 2*d.c)
+	d.p3 = d.p2 + d.c/d.laml
+	d.p4 = d.p3 + d.c/d.lamr
+	return d
+}
+
+// Sample returns a binomial variate
+func (d Binomial) Sample(r *rand.RNG) int64 {
+	var y int64
+	if float64(d.n)*d.r < binomialBTPEMin {
+		y = d.inversion(r)
+	} else {
+		y = d.btpe(r)
+	}
+	if d.p > 0.5 {
+		y = d.n - y
+	}
+	return y
+}
+
+// inversion walks the CDF of Binomial(n, r) from 0, restarting in the
+// rare case that it passes bound
+func (d Binomial) inversion(r *rand.RNG) int64 {
+	if d.r == 0 {
+		return 0
+	}
+	fn := float64(d.n)
+	var x float64
+	px := d.qn
+	u := r.Float64()
+	for u > px {
+		x++
+		if x > d.bound {
+			x, px = 0, d.qn
+			u = r.Float64()
+			continue
+		}
+		u -= px
+		px = (fn - x + 1) * d.r * px / (x * d.q)
+	}
+	return int64(x)
+}
+
+// btpe samples Binomial(n, r) with a triangle, parallelogram and two
+// exponential tails as the envelope; step numbers follow the paper
+func (d Binomial) btpe(r *rand.RNG) int64 {
+	fn := float64(d.n)
+	for {
+		// Step 1: choose a region
+		u := r.Float64() * d.p4
+		v := r.Float64()
+		var y float64
+		switch {
+		case u <= d.p1:
+			// Triangle: accept immediately
+			return int64(math.Floor(d.xm - d.p1*v + u))
+		case u <= d.p2:
+			// Step 2: parallelogram
+			x := d.xl + (u-d.p1)/d.c
+			v = v*d.c + 1 - math.Abs(d.m-x+0.5)/d.p1
+			if v > 1 {
+				continue
+			}
+			y = math.Floor(x)
+		case u <= d.p3:
+			// Step 3: left exponential tail
+			y = math.Floor(d.xl + math.Log(v)/d.laml)
+			if y < 0 || v == 0 {
+				continue
+			}
+			v *= (u - d.p2) * d.laml
+		default:
+			// Step 4: right exponential tail
+			y = math.Floor(d.xr - math.Log(v)/d.lamr)
+			if y > fn || v == 0 {
+				continue
+			}
+			v *= (u - d.p3) * d.lamr
+		}
+
+		// Step 5.0: acceptance test
+		k := math.Abs(y - d.m)
+		if k <= 20 || k >= d.nrq/2-1 {
+			// Step 5.1: evaluate f(y)/f(m) by recursion
+			s := d.r / d.q
+			a := s * (fn + 1)
+			f := 1.0
+			if d.m < y {
+				for i := d.m + 1; i <= y; i++ {
+					f *= a/i - s
+				}
+			} else if d.m > y {
+				for i := y + 1; i <= d.m; i++ {
+					f /= a/i - s
+				}
+			}
+			if v <= f {
+				return int64(y)
+			}
+			continue
+		}
+
+		// Step 5.2: squeeze using upper and lower bounds on log(f(y))
+		rho := (k / d.nrq) * ((k*(k/3+0.625)+1.0/6)/d.nrq + 0.5)
+		t := -k * k / (2 * d.nrq)
+		logV := math.Log(v)
+		if logV < t-rho {
+			return int64(y)
+		}
+		if logV > t+rho {
+			continue
+		}
+
+		// Step 5.3: final comparison with Stirling's formula
+		x1, f1 := y+1, d.m+1
+		z, w := fn+1-d.m, fn-y+1
+		if logV <= d.xm*math.Log(f1/x1)+(fn-d.m+0.5)*math.Log(z/w)+(y-d.m)*math.Log(w*d.r/(x1*d.q))+
+			stirlingTail(f1)+stirlingTail(z)+stirlingTail(x1)+stirlingTail(w) {
+			return int64(y)
+		}
+	}
+}
+
+// stirlingTail returns the correction term of Stirling's series for log(x!)
+func stirlingTail(x float64) float64 {
+	x2 := x * x
+	return (13860 - (462-(132-(99-140/x2)/x2)/x2)/x2) / x / 166320
+}
+
+// Fill fills dst with binomial variates
+func (d Binomial) Fill(r *rand.RNG, dst []int64) {
+	fill(d, r, dst)
+}
+
+// Multinomial is the distribution of the category counts of n draws from
+// a categorical distribution
+// Sampled by conditional binomials, one Binomial per category, so a
+// sample costs O(categories) expected time regardless of n.
+type Multinomial struct {
+	n     int64
+	probs []float64
+}
+
+// NewMultinomial returns a multinomial distribution with n draws over
+// categories with the given weights
+// n must be >= 0; weights must be finite and >= 0 with a positive sum,
+// and are normalized, so they need not sum to 1.
+func NewMultinomial(n int64, weights []float64) (Multinomial, error) {
+	if n < 0 {
+		return Multinomial{}, fmt.Errorf("%w: multinomial n = %d, want n >= 0", ErrInvalidParameter, n)
+	}
+	var sum float64
+	for i, w := range weights {
+		if err := checkFinite("multinomial weight", w); err != nil || w < 0 {
+			return Multinomial{}, fmtNonNegative(fmt.Sprintf("multinomial weight %d", i), w)
+		}
+		sum += w
+	}
+	if !(sum > 0) || math.IsInf(sum, 0) {
+		return Multinomial{}, fmt.Errorf("%w: multinomial weights sum to %v", ErrInvalidParameter, sum)
+	}
+	probs := make([]float64, len(weights))
+	for i, w := range weights {
+		probs[i] = w / sum
+	}
+	return Multinomial{n: n, probs: probs}, nil
+}
+
+// Sample returns the counts of each category, summing to n
+func (d Multinomial) Sample(r *rand.RNG) []int64 {
+	counts := make([]int64, len(d.probs))
+	d.SampleInto(r, counts)
+	return counts
+}
+
+// SampleInto stores the counts of each category in counts
+// Panics if len(counts) is not the number of categories
+func (d Multinomial) SampleInto(r *rand.RNG, counts []int64) {
+	if len(counts) != len(d.probs) {
+		panic("invalid argument to SampleInto")
+	}
+	left, rest := d.n, 1.0
+	for i, p := range d.probs {
+		switch {
+		case left == 0:
+			counts[i] = 0
+			continue
+		case i == len(d.probs)-1 || p >= rest:
+			counts[i] = left
+		default:
+			counts[i] = newBinomial(left, p/rest).Sample(r)
+		}
+		left -= counts[i]
+		rest -= p
+	}
+}
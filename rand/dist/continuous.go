@@ -0,0 +1,342 @@
+package dist
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/vrypan/ring30mix/rand"
+)
+
+// This file contains the continuous distributions sampled by transforming
+// uniform, normal or exponential variates.
+
+// LogNormal is the distribution of exp(X) for X normal with mean mu and
+// standard deviation sigma
+type LogNormal struct {
+	mu, sigma float64
+}
+
+var _ Continuous = LogNormal{}
+
+// NewLogNormal returns a log-normal distribution
+// mu must be finite; sigma must be finite and > 0.
+func NewLogNormal(mu, sigma float64) (LogNormal, error) {
+	if err := checkFinite("log-normal mu", mu); err != nil {
+		return LogNormal{}, err
+	}
+	if err := checkPositive("log-normal sigma", sigma); err != nil {
+		return LogNormal{}, err
+	}
+	return LogNormal{mu: mu, sigma: sigma}, nil
+}
+
+// Sample returns a log-normal variate
+func (d LogNormal) Sample(r *rand.RNG) float64 {
+	return math.Exp(d.mu + d.sigma*r.NormFloat64())
+}
+
+// Fill fills dst with log-normal variates
+func (d LogNormal) Fill(r *rand.RNG, dst []float64) {
+	fill(d, r, dst)
+}
+
+// Weibull is the Weibull distribution with shape k and scale lambda
+// Sampled as lambda * E^(1/k) for E exponential.
+type Weibull struct {
+	k, lambda float64
+}
+
+var _ Continuous = Weibull{}
+
+// NewWeibull returns a Weibull distribution
+// Both k and lambda must be finite and > 0.
+func NewWeibull(k, lambda float64) (Weibull, error) {
+	if err := checkPositive("Weibull k", k); err != nil {
+		return Weibull{}, err
+	}
+	if err := checkPositive("Weibull lambda", lambda); err != nil {
+		return Weibull{}, err
+	}
+	return Weibull{k: k, lambda: lambda}, nil
+}
+
+// Sample returns a Weibull variate
+func (d Weibull) Sample(r *rand.RNG) float64 {
+	return d.lambda * math.Pow(r.ExpFloat64(), 1/d.k)
+}
+
+// Fill fills dst with Weibull variates
+func (d Weibull) Fill(r *rand.RNG, dst []float64) {
+	fill(d, r, dst)
+}
+
+// Cauchy is the Cauchy distribution with location x0 and scale gamma
+type Cauchy struct {
+	x0, gamma float64
+}
+
+var _ Continuous = Cauchy{}
+
+// NewCauchy returns a Cauchy distribution
+// x0 must be finite; gamma must be finite and > 0.
+func NewCauchy(x0, gamma float64) (Cauchy, error) {
+	if err := checkFinite("Cauchy x0", x0); err != nil {
+		return Cauchy{}, err
+	}
+	if err := checkPositive("Cauchy gamma", gamma); err != nil {
+		return Cauchy{}, err
+	}
+	return Cauchy{x0: x0, gamma: gamma}, nil
+}
+
+// Sample returns a Cauchy variate
+func (d Cauchy) Sample(r *rand.RNG) float64 {
+	return d.x0 + d.gamma*math.Tan(math.Pi*(r.Float64Open()-0.5))
+}
+
+// Fill fills dst with Cauchy variates
+func (d Cauchy) Fill(r *rand.RNG, dst []float64) {
+	fill(d, r, dst)
+}
+
+// Pareto is the Pareto (type I) distribution with scale xm and shape alpha
+// Its support is [xm, +Inf).
+type Pareto struct {
+	xm, alpha float64
+}
+
+var _ Continuous = Pareto{}
+
+// NewPareto returns a Pareto distribution
+// Both xm and alpha must be finite and > 0.
+func NewPareto(xm, alpha float64) (Pareto, error) {
+	if err := checkPositive("Pareto xm", xm); err != nil {
+		return Pareto{}, err
+	}
+	if err := checkPositive("Pareto alpha", alpha); err != nil {
+		return Pareto{}, err
+	}
+	return Pareto{xm: xm, alpha: alpha}, nil
+}
+
+// Sample returns a Pareto variate
+func (d Pareto) Sample(r *rand.RNG) float64 {
+	return d.xm * math.Exp(r.ExpFloat64()/d.alpha)
+}
+
+// Fill fills dst with Pareto variates
+func (d Pareto) Fill(r *rand.RNG, dst []float64) {
+	fill(d, r, dst)
+}
+
+// Laplace is the Laplace (double exponential) distribution with location
+// mu and scale b
+type Laplace struct {
+	mu, b float64
+}
+
+var _ Continuous = Laplace{}
+
+// NewLaplace returns a Laplace distribution
+// mu must be finite; b must be finite and > 0.
+func NewLaplace(mu, b float64) (Laplace, error) {
+	if err := checkFinite("Laplace mu", mu); err != nil {
+		return Laplace{}, err
+	}
+	if err := checkPositive("Laplace b", b); err != nil {
+		return Laplace{}, err
+	}
+	return Laplace{mu: mu, b: b}, nil
+}
+
+// Sample returns a Laplace variate
+func (d Laplace) Sample(r *rand.RNG) float64 {
+	// The difference of two exponentials is Laplace
+	e := r.ExpFloat64()
+	return d.mu + d.b*(e-r.ExpFloat64())
+}
+
+// Fill fills dst with Laplace variates
+func (d Laplace) Fill(r *rand.RNG, dst []float64) {
+	fill(d, r, dst)
+}
+
+// Logistic is the logistic distribution with location mu and scale s
+type Logistic struct {
+	mu, s float64
+}
+
+var _ Continuous = Logistic{}
+
+// NewLogistic returns a logistic distribution
+// mu must be finite; s must be finite and > 0.
+func NewLogistic(mu, s float64) (Logistic, error) {
+	if err := checkFinite("logistic mu", mu); err != nil {
+		return Logistic{}, err
+	}
+	if err := checkPositive("logistic s", s); err != nil {
+		return Logistic{}, err
+	}
+	return Logistic{mu: mu, s: s}, nil
+}
+
+// Sample returns a logistic variate
+func (d Logistic) Sample(r *rand.RNG) float64 {
+	u := r.Float64Open()
+	return d.mu + d.s*math.Log(u/(1-u))
+}
+
+// Fill fills dst with logistic variates
+func (d Logistic) Fill(r *rand.RNG, dst []float64) {
+	fill(d, r, dst)
+}
+
+// Gumbel is the Gumbel (type I extreme value) distribution with location
+// mu and scale beta
+type Gumbel struct {
+	mu, beta float64
+}
+
+var _ Continuous = Gumbel{}
+
+// NewGumbel returns a Gumbel distribution
+// mu must be finite; beta must be finite and > 0.
+func NewGumbel(mu, beta float64) (Gumbel, error) {
+	if err := checkFinite("Gumbel mu", mu); err != nil {
+		return Gumbel{}, err
+	}
+	if err := checkPositive("Gumbel beta", beta); err != nil {
+		return Gumbel{}, err
+	}
+	return Gumbel{mu: mu, beta: beta}, nil
+}
+
+// Sample returns a Gumbel variate
+func (d Gumbel) Sample(r *rand.RNG) float64 {
+	return d.mu - d.beta*math.Log(-math.Log(r.Float64Open()))
+}
+
+// Fill fills dst with Gumbel variates
+func (d Gumbel) Fill(r *rand.RNG, dst []float64) {
+	fill(d, r, dst)
+}
+
+// Triangular is the triangular distribution on [a, b] with mode c
+type Triangular struct {
+	a, b, c float64
+	fc      float64 // CDF at the mode
+}
+
+var _ Continuous = Triangular{}
+
+// NewTriangular returns a triangular distribution
+// All parameters must be finite with a <= c <= b and a < b.
+func NewTriangular(a, b, c float64) (Triangular, error) {
+	for _, p := range []struct {
+		name string
+		v    float64
+	}{{"triangular a", a}, {"triangular b", b}, {"triangular c", c}} {
+		if err := checkFinite(p.name, p.v); err != nil {
+			return Triangular{}, err
+		}
+	}
+	if !(a < b) || c < a || c > b {
+		return Triangular{}, fmt.Errorf("%w: triangular a = %v, b = %v, c = %v, want a <= c <= b and a < b",
+			ErrInvalidParameter, a, b, c)
+	}
+	return Triangular{a: a, b: b, c: c, fc: (c - a) / (b - a)}, nil
+}
+
+// Sample returns a triangular variate
+func (d Triangular) Sample(r *rand.RNG) float64 {
+	u := r.Float64()
+	if u < d.fc {
+		return d.a + math.Sqrt(u*(d.b-d.a)*(d.c-d.a))
+	}
+	return d.b - math.Sqrt((1-u)*(d.b-d.a)*(d.b-d.c))
+}
+
+// Fill fills dst with triangular variates
+func (d Triangular) Fill(r *rand.RNG, dst []float64) {
+	fill(d, r, dst)
+}
+
+// VonMises is the von Mises (circular normal) distribution with mean
+// direction mu and concentration kappa
+// Samples are angles in [-Pi, Pi). Sampling uses D. J. Best and N. I.
+// Fisher, "Efficient Simulation of the von Mises Distribution", Applied
+// Statistics 28(2), 1979, with a uniform angle for tiny kappa and a wrapped
+// normal for huge kappa, where the two are indistinguishable.
+type VonMises struct {
+	mu, kappa float64
+	s         float64 // Best-Fisher envelope parameter
+}
+
+var _ Continuous = VonMises{}
+
+// NewVonMises returns a von Mises distribution
+// mu must be finite; kappa must be finite and >= 0.
+func NewVonMises(mu, kappa float64) (VonMises, error) {
+	if err := checkFinite("von Mises mu", mu); err != nil {
+		return VonMises{}, err
+	}
+	if err := checkFinite("von Mises kappa", kappa); err != nil || kappa < 0 {
+		return VonMises{}, fmtNonNegative("von Mises kappa", kappa)
+	}
+	d := VonMises{mu: mu, kappa: kappa}
+	switch {
+	case kappa < 1e-5:
+		// Second-order expansion, avoids cancellation below
+		d.s = 1/kappa + kappa
+	default:
+		t := 1 + math.Sqrt(1+4*kappa*kappa)
+		rho := (t - math.Sqrt(2*t)) / (2 * kappa)
+		d.s = (1 + rho*rho) / (2 * rho)
+	}
+	return d, nil
+}
+
+// Sample returns a von Mises variate
+func (d VonMises) Sample(r *rand.RNG) float64 {
+	var theta float64
+	switch {
+	case d.kappa < 1e-8:
+		return math.Pi * (2*r.Float64() - 1)
+	case d.kappa > 1e6:
+		theta = r.NormFloat64() / math.Sqrt(d.kappa)
+	default:
+		var w float64
+		for {
+			z := math.Cos(math.Pi * r.Float64())
+			w = (1 + d.s*z) / (d.s + z)
+			y := d.kappa * (d.s - w)
+			v := r.Float64Open()
+			if y*(2-y)-v >= 0 || math.Log(y/v)+1-y >= 0 {
+				break
+			}
+		}
+		theta = math.Acos(min(max(w, -1), 1))
+		if r.Uint64()>>63 == 0 {
+			theta = -theta
+		}
+	}
+	return wrapAngle(d.mu + theta)
+}
+
+// wrapAngle maps x to [-Pi, Pi)
+func wrapAngle(x float64) float64 {
+	x = math.Mod(x+math.Pi, 2*math.Pi)
+	if x < 0 {
+		x += 2 * math.Pi
+	}
+	if x -= math.Pi; x >= math.Pi {
+		// Rounding can land on the excluded end point
+		return -math.Pi
+	}
+	return x
+}
+
+// Fill fills dst with von Mises variates
+func (d VonMises) Fill(r *rand.RNG, dst []float64) {
+	fill(d, r, dst)
+}
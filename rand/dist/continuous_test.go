@@ -0,0 +1,161 @@
+package dist
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/vrypan/ring30mix/rand"
+)
+
+func must[D any](d D, err error) D {
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestContinuousFit(t *testing.T) {
+	cases := []struct {
+		name string
+		d    Continuous
+		cdf  func(float64) float64
+	}{
+		{"LogNormal(1, 0.5)", must(NewLogNormal(1, 0.5)), func(x float64) float64 {
+			return 0.5 * math.Erfc(-(math.Log(x)-1)/(0.5*math.Sqrt2))
+		}},
+		{"Weibull(0.7, 2)", must(NewWeibull(0.7, 2)), func(x float64) float64 {
+			return -math.Expm1(-math.Pow(x/2, 0.7))
+		}},
+		{"Weibull(3, 1)", must(NewWeibull(3, 1)), func(x float64) float64 {
+			return -math.Expm1(-x * x * x)
+		}},
+		{"Cauchy(-1, 2)", must(NewCauchy(-1, 2)), func(x float64) float64 {
+			return 0.5 + math.Atan((x+1)/2)/math.Pi
+		}},
+		{"Pareto(2, 1.5)", must(NewPareto(2, 1.5)), func(x float64) float64 {
+			return 1 - math.Pow(2/x, 1.5)
+		}},
+		{"Laplace(3, 0.5)", must(NewLaplace(3, 0.5)), func(x float64) float64 {
+			if x < 3 {
+				return 0.5 * math.Exp((x-3)/0.5)
+			}
+			return 1 - 0.5*math.Exp(-(x-3)/0.5)
+		}},
+		{"Logistic(1, 2)", must(NewLogistic(1, 2)), func(x float64) float64 {
+			return 1 / (1 + math.Exp(-(x-1)/2))
+		}},
+		{"Gumbel(0.5, 2)", must(NewGumbel(0.5, 2)), func(x float64) float64 {
+			return math.Exp(-math.Exp(-(x - 0.5) / 2))
+		}},
+		{"Triangular(0, 4, 1)", must(NewTriangular(0, 4, 1)), func(x float64) float64 {
+			if x < 1 {
+				return x * x / 4
+			}
+			return 1 - (4-x)*(4-x)/12
+		}},
+		{"Triangular(-1, 1, 1)", must(NewTriangular(-1, 1, 1)), func(x float64) float64 {
+			return (x + 1) * (x + 1) / 4
+		}},
+	}
+	for _, c := range cases {
+		checkFit(t, c.name, c.d, c.cdf)
+	}
+}
+
+func TestVonMisesFit(t *testing.T) {
+	// Bins of equal angle, with probabilities from Simpson's rule on the
+	// unnormalized density; the wrapped mean checks the wrap at +-Pi
+	for _, p := range [][2]float64{{0, 1e-9}, {1, 0.5}, {3, 2}, {-2, 50}} {
+		mu, kappa := p[0], p[1]
+		d := must(NewVonMises(mu, kappa))
+		const width = 2 * math.Pi / fitBins
+		probs := make([]float64, fitBins)
+		var total float64
+		for i := range probs {
+			a := -math.Pi + float64(i)*width
+			const steps = 64
+			h := width / steps
+			f := func(x float64) float64 { return math.Exp(kappa * (math.Cos(x-mu) - 1)) }
+			s := f(a) + f(a+width)
+			for j := 1; j < steps; j++ {
+				s += float64(2+2*(j%2)) * f(a+float64(j)*h)
+			}
+			probs[i] = s * h / 3
+			total += probs[i]
+		}
+		counts := make([]int, fitBins)
+		r := rand.New(99)
+		for i := 0; i < fitSamples; i++ {
+			x := d.Sample(r)
+			if x < -math.Pi || x >= math.Pi {
+				t.Fatalf("VonMises(%v, %v) sample %v outside [-Pi, Pi)", mu, kappa, x)
+			}
+			counts[min(int((x+math.Pi)/width), fitBins-1)]++
+		}
+		// Merge bins with tiny expected counts into their neighbours
+		var merged []int
+		var mprobs []float64
+		var c, pr float64
+		for i := range probs {
+			c += float64(counts[i])
+			pr += probs[i] / total
+			if pr*fitSamples >= 20 || i == fitBins-1 {
+				merged = append(merged, int(c))
+				mprobs = append(mprobs, pr)
+				c, pr = 0, 0
+			}
+		}
+		if x2 := chiSquare(merged, mprobs, fitSamples); x2 > fitCritical {
+			t.Errorf("VonMises(%v, %v): chi-square = %.1f over %d bins", mu, kappa, x2, len(merged))
+		}
+	}
+}
+
+func TestFillMatchesSample(t *testing.T) {
+	dists := []Continuous{
+		must(NewGamma(0.5, 2)), must(NewBeta(2, 3)), must(NewChiSquared(4)),
+		must(NewStudentT(3)), must(NewF(2, 7)), must(NewLogNormal(0, 1)),
+		must(NewWeibull(2, 1)), must(NewCauchy(0, 1)), must(NewPareto(1, 3)),
+		must(NewLaplace(0, 1)), must(NewLogistic(0, 1)), must(NewGumbel(0, 1)),
+		must(NewTriangular(0, 1, 0.3)), must(NewVonMises(0, 4)),
+	}
+	for _, d := range dists {
+		got := make([]float64, 37)
+		d.Fill(rand.New(5), got)
+		r := rand.New(5)
+		for i := range got {
+			if want := d.Sample(r); got[i] != want {
+				t.Fatalf("%T: Fill[%d] = %v, Sample = %v", d, i, got[i], want)
+			}
+		}
+	}
+}
+
+func TestContinuousRejectsBadParameters(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+	errs := []error{}
+	add := func(_ any, err error) { errs = append(errs, err) }
+	add(NewLogNormal(nan, 1))
+	add(NewLogNormal(0, 0))
+	add(NewWeibull(-1, 1))
+	add(NewWeibull(1, inf))
+	add(NewCauchy(inf, 1))
+	add(NewCauchy(0, -2))
+	add(NewPareto(0, 1))
+	add(NewPareto(1, nan))
+	add(NewLaplace(0, 0))
+	add(NewLogistic(-inf, 1))
+	add(NewGumbel(0, nan))
+	add(NewTriangular(1, 1, 1))
+	add(NewTriangular(0, 1, 2))
+	add(NewTriangular(0, nan, 0))
+	add(NewVonMises(0, -1))
+	add(NewVonMises(nan, 1))
+	add(NewVonMises(0, inf))
+	for i, err := range errs {
+		if !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("case %d: err = %v, want ErrInvalidParameter", i, err)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package dist
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/vrypan/ring30mix/rand"
+)
+
+// This file contains the Bernoulli, geometric and negative binomial
+// distributions.
+
+// Bernoulli is the distribution of a single trial that succeeds (1) with
+// probability p and fails (0) otherwise
+type Bernoulli struct {
+	p float64
+}
+
+var _ Discrete = Bernoulli{}
+
+// NewBernoulli returns a Bernoulli distribution
+// p must be in [0, 1].
+func NewBernoulli(p float64) (Bernoulli, error) {
+	if err := checkProbability("Bernoulli p", p); err != nil {
+		return Bernoulli{}, err
+	}
+	return Bernoulli{p: p}, nil
+}
+
+// Sample returns 1 with probability p, otherwise 0
+func (d Bernoulli) Sample(r *rand.RNG) int64 {
+	if r.Float64() < d.p {
+		return 1
+	}
+	return 0
+}
+
+// Fill fills dst with Bernoulli variates
+func (d Bernoulli) Fill(r *rand.RNG, dst []int64) {
+	fill(d, r, dst)
+}
+
+// Geometric is the number of failures before the first success in
+// Bernoulli(p) trials, with support 0, 1, 2, ...
+// Sampled by inversion as floor(E / -log(1-p)) for E exponential, in O(1);
+// results beyond math.MaxInt64 are clamped.
+type Geometric struct {
+	lambda float64 // -log(1-p)
+}
+
+var _ Discrete = Geometric{}
+
+// NewGeometric returns a geometric distribution
+// p must be in (0, 1].
+func NewGeometric(p float64) (Geometric, error) {
+	if err := checkProbability("geometric p", p); err != nil || p == 0 {
+		return Geometric{}, fmt.Errorf("%w: geometric p = %v, want a probability in (0, 1]", ErrInvalidParameter, p)
+	}
+	return Geometric{lambda: -math.Log1p(-p)}, nil
+}
+
+// Sample returns a geometric variate
+func (d Geometric) Sample(r *rand.RNG) int64 {
+	return clampInt64(math.Floor(r.ExpFloat64() / d.lambda))
+}
+
+// Fill fills dst with geometric variates
+func (d Geometric) Fill(r *rand.RNG, dst []int64) {
+	fill(d, r, dst)
+}
+
+// clampInt64 converts a non-negative whole float64 to int64, saturating
+// at math.MaxInt64
+func clampInt64(x float64) int64 {
+	if x >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(x)
+}
+
+// NegativeBinomial is the number of failures before the n-th success in
+// Bernoulli(p) trials
+// n need not be an integer (the Pólya distribution). Sampled as a
+// gamma-Poisson mixture, Poisson(Gamma(n, (1-p)/p)), in O(1) expected time.
+type NegativeBinomial struct {
+	g Gamma
+}
+
+var _ Discrete = NegativeBinomial{}
+
+// NewNegativeBinomial returns a negative binomial distribution
+// n must be finite and > 0; p must be in (0, 1].
+func NewNegativeBinomial(n, p float64) (NegativeBinomial, error) {
+	if err := checkPositive("negative binomial n", n); err != nil {
+		return NegativeBinomial{}, err
+	}
+	if err := checkProbability("negative binomial p", p); err != nil || p == 0 {
+		return NegativeBinomial{}, fmt.Errorf("%w: negative binomial p = %v, want a probability in (0, 1]", ErrInvalidParameter, p)
+	}
+	return NegativeBinomial{g: newGamma(n, (1-p)/p)}, nil
+}
+
+// Sample returns a negative binomial variate
+func (d NegativeBinomial) Sample(r *rand.RNG) int64 {
+	if d.g.scale == 0 {
+		// p == 1: every trial succeeds
+		return 0
+	}
+	return newPoisson(d.g.Sample(r)).Sample(r)
+}
+
+// Fill fills dst with negative binomial variates
+func (d NegativeBinomial) Fill(r *rand.RNG, dst []int64) {
+	fill(d, r, dst)
+}
@@ -0,0 +1,278 @@
+package dist
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/vrypan/ring30mix/rand"
+)
+
+// checkPMF compares fitSamples draws of d against its exact PMF with a
+// chi-square test. The support from lo is split into bins with an
+// expected count of at least 20, and everything beyond hi forms one more.
+func checkPMF(t *testing.T, name string, d Discrete, lo, hi int64, pmf func(k int64) float64) {
+	t.Helper()
+	var bins []float64
+	binOf := map[int64]int{}
+	var acc, total float64
+	for k := lo; k <= hi; k++ {
+		binOf[k] = len(bins)
+		acc += pmf(k)
+		total += pmf(k)
+		if acc*fitSamples >= 20 {
+			bins = append(bins, acc)
+			acc = 0
+		}
+	}
+	if rest := 1 - total + acc; rest*fitSamples >= 1e-3 {
+		// The partial last bin and the tail beyond hi
+		bins = append(bins, rest)
+	} else if len(bins) > 0 {
+		bins[len(bins)-1] += rest
+		for k, b := range binOf {
+			binOf[k] = min(b, len(bins)-1)
+		}
+	}
+	counts := make([]int, len(bins))
+	r := rand.New(4242)
+	for i := 0; i < fitSamples; i++ {
+		k := d.Sample(r)
+		if pmf(k) == 0 {
+			t.Fatalf("%s: sample %d outside the support", name, k)
+		}
+		b, ok := binOf[k]
+		if !ok {
+			b = len(bins) - 1
+		}
+		counts[b]++
+	}
+	dof := len(bins) - 1
+	if dof < 1 {
+		// A point mass: the support check above is the whole test
+		return
+	}
+	if x2 := chiSquare(counts, bins, fitSamples); x2 > chiSquareCritical(dof) {
+		t.Errorf("%s: chi-square = %.1f, want <= %.1f (%d dof)", name, x2, chiSquareCritical(dof), dof)
+	}
+}
+
+// chiSquareCritical approximates the p = 0.001 critical value for dof
+// degrees of freedom (Wilson-Hilferty)
+func chiSquareCritical(dof int) float64 {
+	k := float64(dof)
+	z := 3.0902 + 0.5/k // small-dof correction for the right tail
+	c := 1 - 2/(9*k) + z*math.Sqrt(2/(9*k))
+	return k * c * c * c
+}
+
+// logChoose returns log(n choose k)
+func logChoose(n, k int64) float64 {
+	return logFactorial(float64(n)) - logFactorial(float64(k)) - logFactorial(float64(n-k))
+}
+
+func binomialPMF(n int64, p float64) func(int64) float64 {
+	return func(k int64) float64 {
+		if k < 0 || k > n {
+			return 0
+		}
+		switch p {
+		case 0:
+			return b2f(k == 0)
+		case 1:
+			return b2f(k == n)
+		}
+		return math.Exp(logChoose(n, k) + float64(k)*math.Log(p) + float64(n-k)*math.Log1p(-p))
+	}
+}
+
+func b2f(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func TestBernoulliPMF(t *testing.T) {
+	for _, p := range []float64{0, 0.3, 1} {
+		checkPMF(t, fmt.Sprintf("Bernoulli(%v)", p), must(NewBernoulli(p)), 0, 1, binomialPMF(1, p))
+	}
+}
+
+func TestBinomialPMF(t *testing.T) {
+	// Inversion and BTPE, each with p on both sides of 1/2
+	cases := []struct {
+		n int64
+		p float64
+	}{{20, 0.3}, {100, 0.9}, {1000, 0.02}, {100, 0.5}, {1000, 0.3}, {5000, 0.97}, {1 << 40, 1e-9}, {0, 0.5}, {50, 1}}
+	for _, c := range cases {
+		d := must(NewBinomial(c.n, c.p))
+		mean := float64(c.n) * c.p
+		sd := math.Sqrt(mean * (1 - c.p))
+		lo := max(0, int64(mean-8*sd))
+		hi := min(c.n, int64(mean+8*sd)+1)
+		checkPMF(t, fmt.Sprintf("Binomial(%d, %v)", c.n, c.p), d, lo, hi, binomialPMF(c.n, c.p))
+	}
+}
+
+func TestPoissonPMF(t *testing.T) {
+	for _, lambda := range []float64{0, 0.5, 4, 9.9, 10, 37, 1e4} {
+		d := must(NewPoisson(lambda))
+		lo := max(0, int64(lambda-8*math.Sqrt(lambda)))
+		hi := int64(lambda+8*math.Sqrt(lambda)) + 10
+		checkPMF(t, fmt.Sprintf("Poisson(%v)", lambda), d, lo, hi, func(k int64) float64 {
+			if lambda == 0 {
+				return b2f(k == 0)
+			}
+			return math.Exp(-lambda + float64(k)*math.Log(lambda) - logFactorial(float64(k)))
+		})
+	}
+}
+
+func TestGeometricPMF(t *testing.T) {
+	for _, p := range []float64{1, 0.5, 0.05} {
+		checkPMF(t, fmt.Sprintf("Geometric(%v)", p), must(NewGeometric(p)), 0, 400, func(k int64) float64 {
+			return math.Pow(1-p, float64(k)) * p
+		})
+	}
+}
+
+func TestNegativeBinomialPMF(t *testing.T) {
+	for _, c := range [][2]float64{{1, 0.5}, {3, 0.2}, {2.5, 0.7}, {40, 0.5}} {
+		n, p := c[0], c[1]
+		checkPMF(t, fmt.Sprintf("NegativeBinomial(%v, %v)", n, p), must(NewNegativeBinomial(n, p)), 0, 1000, func(k int64) float64 {
+			fk := float64(k)
+			lg1, _ := math.Lgamma(fk + n)
+			lg2, _ := math.Lgamma(n)
+			return math.Exp(lg1 - lg2 - logFactorial(fk) + n*math.Log(p) + fk*math.Log1p(-p))
+		})
+	}
+}
+
+func TestHypergeometricPMF(t *testing.T) {
+	// Simulation and HRUA, including the reflected cases
+	cases := [][3]int64{{20, 7, 5}, {50, 40, 45}, {100, 30, 20}, {1000, 900, 300}, {100000, 2000, 50000}, {10, 10, 10}, {30, 0, 12}}
+	for _, c := range cases {
+		total, good, n := c[0], c[1], c[2]
+		d := must(NewHypergeometric(total, good, n))
+		checkPMF(t, fmt.Sprintf("Hypergeometric(%d, %d, %d)", total, good, n), d, 0, min(good, n), func(k int64) float64 {
+			if k < max(0, n-(total-good)) || k > min(good, n) {
+				return 0
+			}
+			return math.Exp(logChoose(good, k) + logChoose(total-good, n-k) - logChoose(total, n))
+		})
+	}
+}
+
+func TestZipfPMF(t *testing.T) {
+	for _, c := range []struct {
+		n int64
+		s float64
+	}{{1, 2}, {10, 0.5}, {1000, 1}, {1000, 1.5}, {1 << 40, 3}} {
+		var norm float64
+		limit := min(c.n, 1_000_000)
+		for k := limit; k >= 1; k-- {
+			norm += math.Pow(float64(k), -c.s)
+		}
+		checkPMF(t, fmt.Sprintf("Zipf(%d, %v)", c.n, c.s), must(NewZipf(c.n, c.s)), 1, min(c.n, 5000), func(k int64) float64 {
+			return math.Pow(float64(k), -c.s) / norm
+		})
+	}
+}
+
+func TestZetaPMF(t *testing.T) {
+	for _, c := range []struct{ s, zeta float64 }{{2, math.Pi * math.Pi / 6}, {3, 1.2020569031595942}} {
+		checkPMF(t, fmt.Sprintf("Zeta(%v)", c.s), must(NewZeta(c.s)), 1, 5000, func(k int64) float64 {
+			return math.Pow(float64(k), -c.s) / c.zeta
+		})
+	}
+}
+
+func TestMultinomial(t *testing.T) {
+	weights := []float64{1, 0, 3, 6}
+	const n = 50
+	d := must(NewMultinomial(n, weights))
+	r := rand.New(8)
+	marginal := make([]int, n+1)
+	for i := 0; i < fitSamples; i++ {
+		counts := d.Sample(r)
+		var sum int64
+		for _, c := range counts {
+			sum += c
+		}
+		if sum != n || counts[1] != 0 {
+			t.Fatalf("counts %v", counts)
+		}
+		marginal[counts[2]]++
+	}
+	// Each count is Binomial(n, w/sum) on its own
+	pmf := binomialPMF(n, 0.3)
+	probs := make([]float64, n+1)
+	for k := range probs {
+		probs[k] = pmf(int64(k))
+	}
+	var mc []int
+	var mp []float64
+	var c int
+	var p float64
+	for k := range probs {
+		c += marginal[k]
+		p += probs[k]
+		if p*fitSamples >= 20 || k == n {
+			mc = append(mc, c)
+			mp = append(mp, p)
+			c, p = 0, 0
+		}
+	}
+	if x2 := chiSquare(mc, mp, fitSamples); x2 > chiSquareCritical(len(mc)-1) {
+		t.Errorf("Multinomial marginal chi-square = %.1f", x2)
+	}
+}
+
+func TestDiscreteFillMatchesSample(t *testing.T) {
+	dists := []Discrete{
+		must(NewBernoulli(0.4)), must(NewBinomial(10, 0.3)), must(NewBinomial(1000, 0.4)),
+		must(NewPoisson(3)), must(NewPoisson(300)), must(NewGeometric(0.1)),
+		must(NewNegativeBinomial(2, 0.3)), must(NewHypergeometric(100, 30, 40)),
+		must(NewZipf(100, 1.1)), must(NewZeta(2.5)),
+	}
+	for _, d := range dists {
+		got := make([]int64, 37)
+		d.Fill(rand.New(5), got)
+		r := rand.New(5)
+		for i := range got {
+			if want := d.Sample(r); got[i] != want {
+				t.Fatalf("%T: Fill[%d] = %v, Sample = %v", d, i, got[i], want)
+			}
+		}
+	}
+}
+
+func TestDiscreteRejectsBadParameters(t *testing.T) {
+	nan := math.NaN()
+	errs := []error{}
+	add := func(_ any, err error) { errs = append(errs, err) }
+	add(NewBernoulli(-0.1))
+	add(NewBernoulli(nan))
+	add(NewBinomial(-1, 0.5))
+	add(NewBinomial(10, 1.5))
+	add(NewPoisson(-1))
+	add(NewPoisson(math.Inf(1)))
+	add(NewGeometric(0))
+	add(NewNegativeBinomial(0, 0.5))
+	add(NewNegativeBinomial(1, 0))
+	add(NewHypergeometric(10, 11, 5))
+	add(NewHypergeometric(10, 5, -1))
+	add(NewZipf(0, 1))
+	add(NewZipf(10, 0))
+	add(NewZeta(1))
+	add(NewMultinomial(5, nil))
+	add(NewMultinomial(5, []float64{1, -1}))
+	add(NewMultinomial(-5, []float64{1}))
+	for i, err := range errs {
+		if !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("case %d: err = %v, want ErrInvalidParameter", i, err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Package dist samples from common probability distributions using a
+// ring30mix generator.
+//
+// Each distribution is a small value type built by a constructor that
+// validates its parameters and returns an error wrapping
+// ErrInvalidParameter. Sample draws one value; Fill draws len(dst) values
+// and gives exactly the same sequence as repeated calls to Sample. A
+// distribution holds no generator state, so one value can be shared by
+// goroutines that each use their own *rand.RNG.
+package dist
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"math"
+
+	"github.com/vrypan/ring30mix/rand"
+)
+
+// ErrInvalidParameter is returned by constructors given parameters outside
+// the distribution's domain
+var ErrInvalidParameter = errors.New("dist: invalid parameter")
+
+// Continuous is a distribution over the real numbers
+type Continuous interface {
+	Sample(r *rand.RNG) float64
+	Fill(r *rand.RNG, dst []float64)
+}
+
+// Discrete is a distribution over the integers
+type Discrete interface {
+	Sample(r *rand.RNG) int64
+	Fill(r *rand.RNG, dst []int64)
+}
+
+// checkFinite rejects NaN and infinite parameters
+func checkFinite(name string, v float64) error {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return fmt.Errorf("%w: %s = %v, want a finite value", ErrInvalidParameter, name, v)
+	}
+	return nil
+}
+
+// checkPositive rejects parameters that are not finite and > 0
+func checkPositive(name string, v float64) error {
+	if !(v > 0) || math.IsInf(v, 1) {
+		return fmt.Errorf("%w: %s = %v, want a finite value > 0", ErrInvalidParameter, name, v)
+	}
+	return nil
+}
+
+// fmtNonNegative is the error for a parameter that must be finite and >= 0
+func fmtNonNegative(name string, v float64) error {
+	return fmt.Errorf("%w: %s = %v, want a finite value >= 0", ErrInvalidParameter, name, v)
+}
+
+// checkProbability rejects parameters outside [0, 1]
+func checkProbability(name string, p float64) error {
+	if !(p >= 0 && p <= 1) {
+		return fmt.Errorf("%w: %s = %v, want a probability in [0, 1]", ErrInvalidParameter, name, p)
+	}
+	return nil
+}
+
+// logFactorial returns log(k!)
+func logFactorial(k float64) float64 {
+	lg, _ := math.Lgamma(k + 1)
+	return lg
+}
+
+// Sampler is any distribution that draws values of type T
+// Continuous, Discrete and WeightedChoice are Samplers.
+type Sampler[T any] interface {
+	Sample(r *rand.RNG) T
+}
+
+// Samples returns an endless sequence of values drawn from d
+// The sequence draws from r as it is consumed.
+func Samples[T any](d Sampler[T], r *rand.RNG) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for yield(d.Sample(r)) {
+		}
+	}
+}
+
+// fill stores successive samples of s in dst
+// Generic over the concrete sampler so value receivers aren't boxed.
+func fill[T any, S Sampler[T]](s S, r *rand.RNG, dst []T) {
+	for i := range dst {
+		dst[i] = s.Sample(r)
+	}
+}
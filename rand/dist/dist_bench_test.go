@@ -0,0 +1,46 @@
+package dist
+
+import (
+	"testing"
+
+	"github.com/vrypan/ring30mix/rand"
+)
+
+// Categorical sampling over a million categories, where the table no
+// longer fits in cache
+
+func millionWeights() []float64 {
+	r := rand.New(1)
+	w := make([]float64, 1<<20)
+	for i := range w {
+		w[i] = r.ExpFloat64()
+	}
+	return w
+}
+
+func BenchmarkAliasTable_1M(b *testing.B) {
+	t := must(NewAliasTable(millionWeights()))
+	r := rand.New(42)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = t.Sample(r)
+	}
+}
+
+func BenchmarkWeighted_1M(b *testing.B) {
+	w := must(NewWeighted(millionWeights()))
+	r := rand.New(42)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = w.Sample(r)
+	}
+}
+
+func BenchmarkWeighted_Set1M(b *testing.B) {
+	w := must(NewWeighted(millionWeights()))
+	r := rand.New(42)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w.Set(r.IntN(w.Len()), r.Float64())
+	}
+}
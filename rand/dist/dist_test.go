@@ -0,0 +1,176 @@
+package dist
+
+import (
+	"math"
+	"testing"
+
+	"github.com/vrypan/ring30mix/rand"
+)
+
+// Goodness-of-fit helpers shared by the distribution tests
+
+const (
+	fitSamples = 200_000
+	fitBins    = 64
+	// Chi-square critical value for 63 degrees of freedom, p = 0.001
+	fitCritical = 103.4
+)
+
+// checkFit bins fitSamples draws of d by their CDF value into equiprobable
+// bins and fails if the chi-square statistic is too large
+func checkFit(t *testing.T, name string, d Continuous, cdf func(float64) float64) {
+	t.Helper()
+	r := rand.New(12345)
+	counts := make([]int, fitBins)
+	for i := 0; i < fitSamples; i++ {
+		p := cdf(d.Sample(r))
+		if math.IsNaN(p) {
+			t.Fatalf("%s: CDF of sample is NaN", name)
+		}
+		counts[min(max(int(p*fitBins), 0), fitBins-1)]++
+	}
+	expected := make([]float64, fitBins)
+	for i := range expected {
+		expected[i] = 1.0 / fitBins
+	}
+	if x2 := chiSquare(counts, expected, fitSamples); x2 > fitCritical {
+		t.Errorf("%s: chi-square = %.1f, want <= %.1f", name, x2, fitCritical)
+	}
+}
+
+// chiSquare returns the chi-square statistic of counts against the
+// expected probabilities, for n draws
+func chiSquare(counts []int, probs []float64, n int) float64 {
+	var x2 float64
+	for i, c := range counts {
+		e := probs[i] * float64(n)
+		d := float64(c) - e
+		x2 += d * d / e
+	}
+	return x2
+}
+
+// gammaP is the regularized lower incomplete gamma function P(a, x),
+// by series for x < a+1 and by continued fraction otherwise
+func gammaP(a, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	lg, _ := math.Lgamma(a)
+	if x < a+1 {
+		sum, term := 1/a, 1/a
+		for n := 1; n < 1000; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*1e-15 {
+				break
+			}
+		}
+		return sum * math.Exp(-x+a*math.Log(x)-lg)
+	}
+	return 1 - math.Exp(-x+a*math.Log(x)-lg)*contFrac(func(n int) (float64, float64) {
+		if n == 0 {
+			return 0, x + 1 - a
+		}
+		fn := float64(n)
+		return -fn * (fn - a), x + 2*fn + 1 - a
+	})
+}
+
+// betaI is the regularized incomplete beta function I_x(a, b)
+func betaI(a, b, x float64) float64 {
+	switch {
+	case x <= 0:
+		return 0
+	case x >= 1:
+		return 1
+	case x > (a+1)/(a+b+2):
+		return 1 - betaI(b, a, 1-x)
+	}
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	front := math.Exp(lab - la - lb + a*math.Log(x) + b*math.Log1p(-x))
+	return front / a * contFrac(func(n int) (float64, float64) {
+		if n == 0 {
+			return 1, 1
+		}
+		m := float64(n / 2)
+		if n%2 == 1 {
+			return -(a + m) * (a + b + m) * x / ((a + 2*m) * (a + 2*m + 1)), 1
+		}
+		return m * (b - m) * x / ((a + 2*m - 1) * (a + 2*m)), 1
+	})
+}
+
+// contFrac evaluates 1 / (b0 + a1/(b1 + a2/(b2 + ...))) with the modified
+// Lentz method, where term(n) returns (a_n, b_n)
+func contFrac(term func(n int) (float64, float64)) float64 {
+	const tiny = 1e-300
+	_, b0 := term(0)
+	f := b0
+	if f == 0 {
+		f = tiny
+	}
+	c, d := f, 0.0
+	for n := 1; n < 10000; n++ {
+		an, bn := term(n)
+		d = bn + an*d
+		if d == 0 {
+			d = tiny
+		}
+		c = bn + an/c
+		if c == 0 {
+			c = tiny
+		}
+		d = 1 / d
+		delta := c * d
+		f *= delta
+		if math.Abs(delta-1) < 1e-15 {
+			break
+		}
+	}
+	return 1 / f
+}
+
+func TestSpecialFunctions(t *testing.T) {
+	// Known values: P(1, x) = 1 - e^-x, I_x(1, 1) = x, I_x(2, 3), P(5, 3)
+	cases := []struct {
+		name      string
+		got, want float64
+	}{
+		{"P(1, 2)", gammaP(1, 2), 1 - math.Exp(-2)},
+		{"P(1, 0.3)", gammaP(1, 0.3), 1 - math.Exp(-0.3)},
+		{"P(5, 3)", gammaP(5, 3), 0.18473675547622792},
+		{"P(5, 9)", gammaP(5, 9), 0.9450363585048597},
+		{"I_0.3(1, 1)", betaI(1, 1, 0.3), 0.3},
+		{"I_0.4(2, 3)", betaI(2, 3, 0.4), 0.5248},
+		{"I_0.9(2, 3)", betaI(2, 3, 0.9), 0.9963},
+	}
+	for _, c := range cases {
+		if math.Abs(c.got-c.want) > 1e-12 {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestSamples(t *testing.T) {
+	d := must(NewGamma(2, 1))
+	r, ref := rand.New(1), rand.New(1)
+	var n int
+	for x := range Samples(d, r) {
+		if x != d.Sample(ref) {
+			t.Fatalf("Samples value %d does not match Sample", n)
+		}
+		if n++; n == 100 {
+			break
+		}
+	}
+	c := must(NewWeightedChoice([]string{"x"}, []float64{1}))
+	for s := range Samples[string](c, r) {
+		if s != "x" {
+			t.Fatalf("got %q", s)
+		}
+		break
+	}
+}
@@ -0,0 +1,217 @@
+package dist
+
+import (
+	"math"
+
+	"github.com/vrypan/ring30mix/rand"
+)
+
+// This file contains the gamma distribution and the distributions built
+// from gamma variates: beta, chi-squared, Student's t and F.
+
+// Gamma is the gamma distribution with a shape and a scale
+// The mean is shape*scale. Sampling uses G. Marsaglia and W. W. Tsang, "A
+// Simple Method for Generating Gamma Variables", ACM TOMS 26(3), 2000;
+// shapes below 1 are boosted with Gamma(shape) = Gamma(shape+1) * U^(1/shape).
+type Gamma struct {
+	shape, scale float64
+	d, c         float64 // Marsaglia-Tsang constants for max(shape, shape+1)
+}
+
+var _ Continuous = Gamma{}
+
+// NewGamma returns a gamma distribution
+// Both shape and scale must be finite and > 0.
+func NewGamma(shape, scale float64) (Gamma, error) {
+	if err := checkPositive("gamma shape", shape); err != nil {
+		return Gamma{}, err
+	}
+	if err := checkPositive("gamma scale", scale); err != nil {
+		return Gamma{}, err
+	}
+	return newGamma(shape, scale), nil
+}
+
+// newGamma builds a gamma distribution from validated parameters
+func newGamma(shape, scale float64) Gamma {
+	a := shape
+	if a < 1 {
+		a++
+	}
+	d := a - 1.0/3
+	return Gamma{shape: shape, scale: scale, d: d, c: 1 / math.Sqrt(9*d)}
+}
+
+// Sample returns a gamma variate
+func (g Gamma) Sample(r *rand.RNG) float64 {
+	return g.scale * g.standard(r)
+}
+
+// standard returns a gamma variate with scale 1
+func (g Gamma) standard(r *rand.RNG) float64 {
+	var x float64
+	for {
+		z := r.NormFloat64()
+		v := 1 + g.c*z
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := r.Float64Open()
+		z2 := z * z
+		if u < 1-0.0331*z2*z2 || math.Log(u) < 0.5*z2+g.d*(1-v+math.Log(v)) {
+			x = g.d * v
+			break
+		}
+	}
+	if g.shape < 1 {
+		x *= math.Exp(math.Log(r.Float64Open()) / g.shape)
+	}
+	return x
+}
+
+// Fill fills dst with gamma variates
+func (g Gamma) Fill(r *rand.RNG, dst []float64) {
+	fill(g, r, dst)
+}
+
+// Beta is the beta distribution on [0, 1] with shapes alpha and beta
+// Sampling uses X/(X+Y) for gamma variates X and Y, except when both
+// shapes are at most 1, where Jöhnk's algorithm in log space stays
+// accurate as X and Y underflow.
+type Beta struct {
+	alpha, beta float64
+	x, y        Gamma
+}
+
+var _ Continuous = Beta{}
+
+// NewBeta returns a beta distribution
+// Both alpha and beta must be finite and > 0.
+func NewBeta(alpha, beta float64) (Beta, error) {
+	if err := checkPositive("beta alpha", alpha); err != nil {
+		return Beta{}, err
+	}
+	if err := checkPositive("beta beta", beta); err != nil {
+		return Beta{}, err
+	}
+	return Beta{alpha: alpha, beta: beta, x: newGamma(alpha, 1), y: newGamma(beta, 1)}, nil
+}
+
+// Sample returns a beta variate
+func (b Beta) Sample(r *rand.RNG) float64 {
+	if b.alpha <= 1 && b.beta <= 1 {
+		return b.johnk(r)
+	}
+	x := b.x.standard(r)
+	y := b.y.standard(r)
+	return x / (x + y)
+}
+
+// johnk samples with Jöhnk's algorithm, keeping U^(1/alpha) and
+// V^(1/beta) as logarithms
+func (b Beta) johnk(r *rand.RNG) float64 {
+	for {
+		lx := math.Log(r.Float64Open()) / b.alpha
+		ly := math.Log(r.Float64Open()) / b.beta
+		m := max(lx, ly)
+		ex, ey := math.Exp(lx-m), math.Exp(ly-m)
+		// Accept when U^(1/alpha) + V^(1/beta) <= 1
+		if m+math.Log(ex+ey) <= 0 {
+			return ex / (ex + ey)
+		}
+	}
+}
+
+// Fill fills dst with beta variates
+func (b Beta) Fill(r *rand.RNG, dst []float64) {
+	fill(b, r, dst)
+}
+
+// ChiSquared is the chi-squared distribution with k degrees of freedom
+// It is Gamma(k/2, 2); k need not be an integer.
+type ChiSquared struct {
+	g Gamma
+}
+
+var _ Continuous = ChiSquared{}
+
+// NewChiSquared returns a chi-squared distribution
+// k must be finite and > 0.
+func NewChiSquared(k float64) (ChiSquared, error) {
+	if err := checkPositive("chi-squared degrees of freedom", k); err != nil {
+		return ChiSquared{}, err
+	}
+	return ChiSquared{g: newGamma(k/2, 2)}, nil
+}
+
+// Sample returns a chi-squared variate
+func (c ChiSquared) Sample(r *rand.RNG) float64 {
+	return c.g.Sample(r)
+}
+
+// Fill fills dst with chi-squared variates
+func (c ChiSquared) Fill(r *rand.RNG, dst []float64) {
+	fill(c, r, dst)
+}
+
+// StudentT is Student's t distribution with nu degrees of freedom
+// Sampled as Z / sqrt(V/nu) for a standard normal Z and chi-squared V.
+type StudentT struct {
+	nu float64
+	v  Gamma
+}
+
+var _ Continuous = StudentT{}
+
+// NewStudentT returns a Student's t distribution
+// nu must be finite and > 0.
+func NewStudentT(nu float64) (StudentT, error) {
+	if err := checkPositive("Student's t degrees of freedom", nu); err != nil {
+		return StudentT{}, err
+	}
+	return StudentT{nu: nu, v: newGamma(nu/2, 2)}, nil
+}
+
+// Sample returns a Student's t variate
+func (t StudentT) Sample(r *rand.RNG) float64 {
+	z := r.NormFloat64()
+	return z / math.Sqrt(t.v.Sample(r)/t.nu)
+}
+
+// Fill fills dst with Student's t variates
+func (t StudentT) Fill(r *rand.RNG, dst []float64) {
+	fill(t, r, dst)
+}
+
+// F is the F distribution with d1 and d2 degrees of freedom
+// Sampled as (U/d1) / (V/d2) for chi-squared U and V.
+type F struct {
+	d1, d2 float64
+	u, v   Gamma
+}
+
+var _ Continuous = F{}
+
+// NewF returns an F distribution
+// Both d1 and d2 must be finite and > 0.
+func NewF(d1, d2 float64) (F, error) {
+	if err := checkPositive("F d1", d1); err != nil {
+		return F{}, err
+	}
+	if err := checkPositive("F d2", d2); err != nil {
+		return F{}, err
+	}
+	return F{d1: d1, d2: d2, u: newGamma(d1/2, 2), v: newGamma(d2/2, 2)}, nil
+}
+
+// Sample returns an F variate
+func (f F) Sample(r *rand.RNG) float64 {
+	u := f.u.Sample(r) / f.d1
+	return u / (f.v.Sample(r) / f.d2)
+}
+
+// Fill fills dst with F variates
+func (f F) Fill(r *rand.RNG, dst []float64) {
+	fill(f, r, dst)
+}
@@ -0,0 +1,101 @@
+package dist
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestGammaFit(t *testing.T) {
+	for _, shape := range []float64{0.1, 0.5, 1, 2.5, 30} {
+		g, err := NewGamma(shape, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkFit(t, fmt.Sprintf("Gamma(%v, 3)", shape), g, func(x float64) float64 {
+			return gammaP(shape, x/3)
+		})
+	}
+}
+
+func TestBetaFit(t *testing.T) {
+	// Both the gamma ratio and Jöhnk's algorithm
+	for _, p := range [][2]float64{{0.2, 0.3}, {0.5, 0.5}, {1, 1}, {0.5, 4}, {2, 5}, {40, 3}} {
+		b, err := NewBeta(p[0], p[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkFit(t, fmt.Sprintf("Beta(%v, %v)", p[0], p[1]), b, func(x float64) float64 {
+			return betaI(p[0], p[1], x)
+		})
+	}
+}
+
+func TestChiSquaredFit(t *testing.T) {
+	for _, k := range []float64{1, 3, 10.5} {
+		c, err := NewChiSquared(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkFit(t, fmt.Sprintf("ChiSquared(%v)", k), c, func(x float64) float64 {
+			return gammaP(k/2, x/2)
+		})
+	}
+}
+
+func TestStudentTFit(t *testing.T) {
+	for _, nu := range []float64{1, 2.5, 30} {
+		s, err := NewStudentT(nu)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkFit(t, fmt.Sprintf("StudentT(%v)", nu), s, func(x float64) float64 {
+			tail := 0.5 * betaI(nu/2, 0.5, nu/(nu+x*x))
+			if x > 0 {
+				return 1 - tail
+			}
+			return tail
+		})
+	}
+}
+
+func TestFFit(t *testing.T) {
+	for _, p := range [][2]float64{{1, 1}, {5, 2}, {10, 30}} {
+		f, err := NewF(p[0], p[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkFit(t, fmt.Sprintf("F(%v, %v)", p[0], p[1]), f, func(x float64) float64 {
+			return betaI(p[0]/2, p[1]/2, p[0]*x/(p[0]*x+p[1]))
+		})
+	}
+}
+
+func TestGammaFamilyRejectsBadParameters(t *testing.T) {
+	bad := []float64{0, -1, math.NaN(), math.Inf(1)}
+	for _, v := range bad {
+		errs := []error{}
+		_, err := NewGamma(v, 1)
+		errs = append(errs, err)
+		_, err = NewGamma(1, v)
+		errs = append(errs, err)
+		_, err = NewBeta(v, 1)
+		errs = append(errs, err)
+		_, err = NewBeta(1, v)
+		errs = append(errs, err)
+		_, err = NewChiSquared(v)
+		errs = append(errs, err)
+		_, err = NewStudentT(v)
+		errs = append(errs, err)
+		_, err = NewF(v, 1)
+		errs = append(errs, err)
+		_, err = NewF(1, v)
+		errs = append(errs, err)
+		for i, err := range errs {
+			if !errors.Is(err, ErrInvalidParameter) {
+				t.Errorf("constructor %d accepted %v: err = %v", i, v, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package dist
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/vrypan/ring30mix/rand"
+)
+
+// This file contains the hypergeometric distribution.
+
+// hypergeometricHRUAMin is the smallest draw count (or complement)
+// sampled with the ratio-of-uniforms method; below it drawing one item
+// at a time is cheaper
+const hypergeometricHRUAMin = 10
+
+// Hypergeometric is the number of successes in n draws without
+// replacement from a population of total items, successes of which count
+// as successes
+// Sampling is O(1) expected time: for n and total-n of at least 10 it uses
+// the ratio-of-uniforms method HRUA of E. Stadlober, "Sampling from
+// Poisson, Binomial and Hypergeometric Distributions: Ratio of Uniforms as
+// a Simple and Fast Alternative", 1989; otherwise it simulates the draws.
+type Hypergeometric struct {
+	total, successes, n int64
+	// HRUA constants
+	sample, minGood, maxGood int64
+	a, h, g, b               float64
+}
+
+var _ Discrete = Hypergeometric{}
+
+// NewHypergeometric returns a hypergeometric distribution
+// Requires 0 <= successes <= total and 0 <= n <= total.
+func NewHypergeometric(total, successes, n int64) (Hypergeometric, error) {
+	if total < 0 || successes < 0 || successes > total || n < 0 || n > total {
+		return Hypergeometric{}, fmt.Errorf("%w: hypergeometric total = %d, successes = %d, n = %d, want 0 <= successes, n <= total",
+			ErrInvalidParameter, total, successes, n)
+	}
+	d := Hypergeometric{total: total, successes: successes, n: n}
+	if !d.useHRUA() {
+		return d, nil
+	}
+	const d1, d2 = 1.7155277699214135, 0.8989161620588988 // 2*sqrt(2/e), 3 - 2*sqrt(3/e)
+	d.sample = min(n, total-n)
+	d.minGood = min(successes, total-successes)
+	d.maxGood = max(successes, total-successes)
+	sample, minGood, maxGood, pop := float64(d.sample), float64(d.minGood), float64(d.maxGood), float64(total)
+	p, q := minGood/pop, maxGood/pop
+	d.a = sample*p + 0.5
+	c := math.Sqrt((pop-sample)*sample*p*q/(pop-1) + 0.5)
+	d.h = d1*c + d2
+	m := math.Floor((sample + 1) * (minGood + 1) / (pop + 2))
+	d.g = logFactorial(m) + logFactorial(minGood-m) + logFactorial(sample-m) + logFactorial(maxGood-sample+m)
+	d.b = min(min(sample, minGood)+1, math.Floor(d.a+16*c))
+	return d, nil
+}
+
+// useHRUA reports whether Sample uses the ratio-of-uniforms method
+func (d Hypergeometric) useHRUA() bool {
+	return d.n >= hypergeometricHRUAMin && d.n <= d.total-hypergeometricHRUAMin
+}
+
+// Sample returns a hypergeometric variate
+func (d Hypergeometric) Sample(r *rand.RNG) int64 {
+	if !d.useHRUA() {
+		return d.simulate(r)
+	}
+	sample, minGood, maxGood := float64(d.sample), float64(d.minGood), float64(d.maxGood)
+	var k float64
+	for {
+		u := r.Float64Open()
+		v := r.Float64()
+		x := d.a + d.h*(v-0.5)/u
+		if x < 0 || x >= d.b {
+			continue
+		}
+		k = math.Floor(x)
+		t := d.g - (logFactorial(k) + logFactorial(minGood-k) + logFactorial(sample-k) + logFactorial(maxGood-sample+k))
+		if u*(4-u)-3 <= t {
+			break
+		}
+		if u*(u-t) >= 1 {
+			continue
+		}
+		if 2*math.Log(u) <= t {
+			break
+		}
+	}
+	// Undo the reductions to the smaller draw count and success class
+	y := int64(k)
+	if d.successes > d.total-d.successes {
+		y = d.sample - y
+	}
+	if d.sample < d.n {
+		y = d.successes - y
+	}
+	return y
+}
+
+// simulate draws min(n, total-n) items one at a time
+func (d Hypergeometric) simulate(r *rand.RNG) int64 {
+	drawn := min(d.n, d.total-d.n)
+	left, good := d.total, d.successes // items and successes not yet drawn
+	k := drawn
+	for ; k > 0 && good > 0 && left > good; k-- {
+		if r.Int64N(left) < good {
+			good--
+		}
+		left--
+	}
+	if left == good {
+		// Only successes are left, so the remaining draws are all successes
+		good -= k
+	}
+	if drawn < d.n {
+		// The undrawn items are the n we want
+		return good
+	}
+	return d.successes - good
+}
+
+// Fill fills dst with hypergeometric variates
+func (d Hypergeometric) Fill(r *rand.RNG, dst []int64) {
+	fill(d, r, dst)
+}
@@ -0,0 +1,85 @@
+package dist
+
+import (
+	"math"
+
+	"github.com/vrypan/ring30mix/rand"
+)
+
+// This file contains the Poisson distribution.
+
+// poissonPTRSMin is the smallest mean sampled with PTRS; below it the
+// multiplication method needs about lambda+1 uniforms, which is cheaper
+const poissonPTRSMin = 10
+
+// Poisson is the Poisson distribution with mean lambda
+// Sampling is O(1) expected time: the multiplication method for
+// lambda < 10, and for larger means the transformed rejection with squeeze
+// (PTRS) of W. Hörmann, "The Transformed Rejection Method for Generating
+// Poisson Random Variables", Insurance: Mathematics and Economics 12(1),
+// 1993.
+type Poisson struct {
+	lambda float64
+	// PTRS constants
+	expNeg, logLambda, a, b, logInvAlpha, vr float64
+}
+
+var _ Discrete = Poisson{}
+
+// NewPoisson returns a Poisson distribution
+// lambda must be finite and >= 0.
+func NewPoisson(lambda float64) (Poisson, error) {
+	if err := checkFinite("Poisson lambda", lambda); err != nil || lambda < 0 {
+		return Poisson{}, fmtNonNegative("Poisson lambda", lambda)
+	}
+	return newPoisson(lambda), nil
+}
+
+// newPoisson builds a Poisson distribution from a validated mean
+func newPoisson(lambda float64) Poisson {
+	d := Poisson{lambda: lambda}
+	if lambda < poissonPTRSMin {
+		d.expNeg = math.Exp(-lambda)
+		return d
+	}
+	slam := math.Sqrt(lambda)
+	d.logLambda = math.Log(lambda)
+	d.b = 0.931 + 2.53*slam
+	d.a = -0.059 + 0.02483*d.b
+	d.logInvAlpha = math.Log(1.1239 + 1.1328/(d.b-3.4))
+	d.vr = 0.9277 - 3.6224/(d.b-2)
+	return d
+}
+
+// Sample returns a Poisson variate
+func (d Poisson) Sample(r *rand.RNG) int64 {
+	if d.lambda < poissonPTRSMin {
+		// Count uniforms until their product drops below e^-lambda
+		var k int64
+		for p := r.Float64(); p > d.expNeg; p *= r.Float64() {
+			k++
+		}
+		return k
+	}
+	for {
+		u := r.Float64() - 0.5
+		v := r.Float64Open()
+		us := 0.5 - math.Abs(u)
+		k := math.Floor((2*d.a/us+d.b)*u + d.lambda + 0.43)
+		if us >= 0.07 && v <= d.vr {
+			return clampInt64(k)
+		}
+		if k < 0 || (us < 0.013 && v > us) {
+			continue
+		}
+		if math.Log(v)+d.logInvAlpha-math.Log(d.a/(us*us)+d.b) <=
+			-d.lambda+k*d.logLambda-logFactorial(k) {
+			return clampInt64(k)
+		}
+	}
+}
+
+// Fill fills dst with Poisson variates
+func (d Poisson) Fill(r *rand.RNG, dst []int64) {
+	fill(d, r, dst)
+}
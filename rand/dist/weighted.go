@@ -0,0 +1,249 @@
+package dist
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/vrypan/ring30mix/rand"
+)
+
+// This file contains samplers for categorical distributions given by
+// weights: a static alias table, a Fenwick tree that allows weight
+// updates, and a generic wrapper that returns items instead of indices.
+
+// checkWeights validates weights and returns their sum
+// Weights must be finite and >= 0; the sum must be finite, and positive
+// unless allowZero is set.
+func checkWeights(weights []float64, allowZero bool) (float64, error) {
+	var sum float64
+	for i, w := range weights {
+		if err := checkFinite("weight", w); err != nil || w < 0 {
+			return 0, fmtNonNegative(fmt.Sprintf("weight %d", i), w)
+		}
+		sum += w
+	}
+	if math.IsInf(sum, 0) || (sum == 0 && !allowZero) {
+		return 0, fmt.Errorf("%w: weights sum to %v", ErrInvalidParameter, sum)
+	}
+	return sum, nil
+}
+
+// AliasTable samples indices in proportion to fixed weights in O(1)
+// Built with Vose's method: M. D. Vose, "A Linear Algorithm for Generating
+// Random Numbers with a Given Distribution", IEEE TSE 17(9), 1991. Each
+// sample costs one Uint64: the high half of its product with the number of
+// columns picks a column, and the low half is the biased coin between the
+// column and its alias. The column choice is biased by at most n/2^64.
+type AliasTable struct {
+	accept []uint64 // keep the column if the coin is below accept
+	alias  []uint32
+}
+
+var _ Discrete = (*AliasTable)(nil)
+
+// NewAliasTable builds an alias table from weights
+// Weights must be finite and >= 0 with a positive, finite sum; there may
+// be at most 2^32 of them.
+func NewAliasTable(weights []float64) (*AliasTable, error) {
+	sum, err := checkWeights(weights, false)
+	if err != nil {
+		return nil, err
+	}
+	n := len(weights)
+	if uint64(n) > math.MaxUint32+1 {
+		return nil, fmt.Errorf("%w: %d weights, want at most 2^32", ErrInvalidParameter, n)
+	}
+
+	t := &AliasTable{accept: make([]uint64, n), alias: make([]uint32, n)}
+	p := make([]float64, n)
+	var small, large []uint32
+	for i, w := range weights {
+		p[i] = w * float64(n) / sum
+		if p[i] < 1 {
+			small = append(small, uint32(i))
+		} else {
+			large = append(large, uint32(i))
+		}
+	}
+	for len(small) > 0 && len(large) > 0 {
+		s, l := small[len(small)-1], large[len(large)-1]
+		small = small[:len(small)-1]
+		t.accept[s] = probToThreshold(p[s])
+		t.alias[s] = l
+		p[l] = (p[l] + p[s]) - 1
+		if p[l] < 1 {
+			large = large[:len(large)-1]
+			small = append(small, l)
+		}
+	}
+	// Whatever is left is 1 up to rounding
+	for _, i := range append(small, large...) {
+		t.accept[i] = math.MaxUint64
+		t.alias[i] = i
+	}
+	return t, nil
+}
+
+// probToThreshold scales a probability in [0, 1) to a uint64 threshold
+func probToThreshold(p float64) uint64 {
+	if a := p * 0x1p64; a < 0x1p64 {
+		return uint64(a)
+	}
+	return math.MaxUint64
+}
+
+// Len returns the number of categories
+func (t *AliasTable) Len() int {
+	return len(t.accept)
+}
+
+// Sample returns an index in [0, Len()) with probability proportional to
+// its weight
+func (t *AliasTable) Sample(r *rand.RNG) int64 {
+	i, coin := bits.Mul64(r.Uint64(), uint64(len(t.accept)))
+	if coin < t.accept[i] {
+		return int64(i)
+	}
+	return int64(t.alias[i])
+}
+
+// Fill fills dst with sampled indices
+func (t *AliasTable) Fill(r *rand.RNG, dst []int64) {
+	fill(t, r, dst)
+}
+
+// Weighted samples indices in proportion to weights that can change
+// Weights live in a Fenwick (binary indexed) tree, so Set and Sample are
+// O(log n). Repeated updates accumulate floating-point rounding in the
+// partial sums; Reset rebuilds them exactly.
+type Weighted struct {
+	weights []float64
+	tree    []float64 // tree[i] sums weights (i - i&-i, i], 1-based
+}
+
+// NewWeighted creates a dynamic sampler from initial weights
+// Weights must be finite and >= 0; they may all be zero.
+func NewWeighted(weights []float64) (*Weighted, error) {
+	w := &Weighted{}
+	if err := w.Reset(weights); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Reset replaces all weights, in O(n)
+func (w *Weighted) Reset(weights []float64) error {
+	if _, err := checkWeights(weights, true); err != nil {
+		return err
+	}
+	w.weights = append(w.weights[:0], weights...)
+	w.tree = append(w.tree[:0], 0)
+	w.tree = append(w.tree, weights...)
+	for i := 1; i < len(w.tree); i++ {
+		if j := i + i&-i; j < len(w.tree) {
+			w.tree[j] += w.tree[i]
+		}
+	}
+	return nil
+}
+
+// Len returns the number of categories
+func (w *Weighted) Len() int {
+	return len(w.weights)
+}
+
+// Weight returns the weight of index i
+func (w *Weighted) Weight(i int) float64 {
+	return w.weights[i]
+}
+
+// Set changes the weight of index i
+// The weight must be finite and >= 0, and i in [0, Len()).
+func (w *Weighted) Set(i int, weight float64) error {
+	if i < 0 || i >= len(w.weights) {
+		return fmt.Errorf("%w: index %d out of range [0, %d)", ErrInvalidParameter, i, len(w.weights))
+	}
+	if err := checkFinite("weight", weight); err != nil || weight < 0 {
+		return fmtNonNegative(fmt.Sprintf("weight %d", i), weight)
+	}
+	delta := weight - w.weights[i]
+	w.weights[i] = weight
+	for j := i + 1; j < len(w.tree); j += j & -j {
+		w.tree[j] += delta
+	}
+	return nil
+}
+
+// Total returns the sum of the weights
+func (w *Weighted) Total() float64 {
+	var sum float64
+	for j := len(w.weights); j > 0; j -= j & -j {
+		sum += w.tree[j]
+	}
+	return sum
+}
+
+// Sample returns an index with probability proportional to its weight
+// Panics if all weights are zero
+func (w *Weighted) Sample(r *rand.RNG) int64 {
+	total := w.Total()
+	if !(total > 0) {
+		panic("dist: Sample from Weighted with zero total weight")
+	}
+	n := len(w.weights)
+	top := 1 << (bits.Len(uint(n)) - 1)
+	for {
+		// Find the first index whose prefix sum exceeds the target
+		target := r.Float64() * total
+		pos := 0
+		for step := top; step > 0; step >>= 1 {
+			if next := pos + step; next <= n && w.tree[next] <= target {
+				pos = next
+				target -= w.tree[next]
+			}
+		}
+		// Rounding in the partial sums can land past the end or on a
+		// zero weight; draw again
+		if pos < n && w.weights[pos] > 0 {
+			return int64(pos)
+		}
+	}
+}
+
+// Fill fills dst with sampled indices
+func (w *Weighted) Fill(r *rand.RNG, dst []int64) {
+	fill(w, r, dst)
+}
+
+// WeightedChoice draws items in proportion to fixed weights in O(1), using
+// an AliasTable
+type WeightedChoice[T any] struct {
+	items []T
+	table *AliasTable
+}
+
+// NewWeightedChoice creates a weighted choice over items
+// weights[i] is the weight of items[i]; the slices must have the same
+// length, and the weights must be valid for NewAliasTable. items is not
+// copied.
+func NewWeightedChoice[T any](items []T, weights []float64) (*WeightedChoice[T], error) {
+	if len(items) != len(weights) {
+		return nil, fmt.Errorf("%w: %d items but %d weights", ErrInvalidParameter, len(items), len(weights))
+	}
+	t, err := NewAliasTable(weights)
+	if err != nil {
+		return nil, err
+	}
+	return &WeightedChoice[T]{items: items, table: t}, nil
+}
+
+// Sample returns an item with probability proportional to its weight
+func (c *WeightedChoice[T]) Sample(r *rand.RNG) T {
+	return c.items[c.table.Sample(r)]
+}
+
+// Fill fills dst with sampled items
+func (c *WeightedChoice[T]) Fill(r *rand.RNG, dst []T) {
+	fill(c, r, dst)
+}
@@ -0,0 +1,142 @@
+package dist
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/vrypan/ring30mix/rand"
+)
+
+// checkCategorical compares fitSamples draws of d with the distribution
+// given by weights
+func checkCategorical(t *testing.T, name string, d Discrete, weights []float64) {
+	t.Helper()
+	var sum float64
+	for _, w := range weights {
+		sum += w
+	}
+	checkPMF(t, name, d, 0, int64(len(weights)-1), func(k int64) float64 {
+		if k < 0 || k >= int64(len(weights)) {
+			return 0
+		}
+		return weights[k] / sum
+	})
+}
+
+func testWeights() map[string][]float64 {
+	skewed := make([]float64, 1000)
+	for i := range skewed {
+		skewed[i] = math.Pow(float64(i+1), -1.2)
+	}
+	return map[string][]float64{
+		"single":     {2.5},
+		"with zeros": {0, 1, 0, 0, 3, 0.5, 0},
+		"uniform":    {1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+		"tiny":       {1e-300, 2e-300, 1e-300},
+		"skewed":     skewed,
+	}
+}
+
+func TestAliasTable(t *testing.T) {
+	for name, w := range testWeights() {
+		table, err := NewAliasTable(w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if table.Len() != len(w) {
+			t.Fatalf("%s: Len = %d", name, table.Len())
+		}
+		checkCategorical(t, "AliasTable "+name, table, w)
+	}
+}
+
+func TestWeighted(t *testing.T) {
+	for name, w := range testWeights() {
+		ws, err := NewWeighted(w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkCategorical(t, "Weighted "+name, ws, w)
+	}
+}
+
+func TestWeightedUpdates(t *testing.T) {
+	r := rand.New(3)
+	w := make([]float64, 300)
+	ws, err := NewWeighted(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10_000; i++ {
+		j := r.IntN(len(w))
+		w[j] = float64(r.IntN(5)) * r.Float64()
+		if err := ws.Set(j, w[j]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var sum float64
+	for j := range w {
+		sum += w[j]
+		if ws.Weight(j) != w[j] {
+			t.Fatalf("Weight(%d) = %v, want %v", j, ws.Weight(j), w[j])
+		}
+	}
+	if math.Abs(ws.Total()-sum) > 1e-9*sum {
+		t.Fatalf("Total = %v, want %v", ws.Total(), sum)
+	}
+	checkCategorical(t, "Weighted after updates", ws, w)
+
+	if err := ws.Set(len(w), 1); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("Set out of range: err = %v", err)
+	}
+	if err := ws.Set(0, -1); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("Set negative: err = %v", err)
+	}
+}
+
+func TestWeightedZeroTotalPanics(t *testing.T) {
+	ws, err := NewWeighted([]float64{0, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Sample with zero total did not panic")
+		}
+	}()
+	ws.Sample(rand.New(1))
+}
+
+func TestWeightedChoice(t *testing.T) {
+	items := []string{"a", "b", "c"}
+	c, err := NewWeightedChoice(items, []float64{1, 0, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := rand.New(9)
+	counts := map[string]int{}
+	got := make([]string, 40_000)
+	c.Fill(r, got)
+	for _, s := range got {
+		counts[s]++
+	}
+	if counts["b"] != 0 || math.Abs(float64(counts["c"])/float64(len(got))-0.75) > 0.01 {
+		t.Fatalf("counts = %v", counts)
+	}
+
+	if _, err := NewWeightedChoice(items, []float64{1, 2}); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("length mismatch: err = %v", err)
+	}
+}
+
+func TestWeightsRejected(t *testing.T) {
+	for _, w := range [][]float64{nil, {0, 0}, {1, -1}, {math.NaN()}, {math.Inf(1)}, {math.MaxFloat64, math.MaxFloat64}} {
+		if _, err := NewAliasTable(w); !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("NewAliasTable(%v): err = %v", w, err)
+		}
+	}
+	if _, err := NewWeighted([]float64{1, math.NaN()}); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("NewWeighted accepted NaN: err = %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package dist
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/vrypan/ring30mix/rand"
+)
+
+// This file contains the Zipf and zeta distributions, the finite and
+// infinite power laws over the positive integers.
+
+// Zipf is the distribution over 1..n with P(k) proportional to k^-s
+// Sampled in O(1) expected time by the rejection-inversion method of
+// W. Hörmann and G. Derflinger, "Rejection-inversion to generate variates
+// from monotone discrete distributions", ACM TOMACS 6(3), 1996.
+type Zipf struct {
+	n               int64
+	s               float64
+	hx1, hn, accept float64
+}
+
+var _ Discrete = Zipf{}
+
+// NewZipf returns a Zipf distribution
+// n must be >= 1; s must be finite and > 0.
+func NewZipf(n int64, s float64) (Zipf, error) {
+	if n < 1 {
+		return Zipf{}, fmt.Errorf("%w: Zipf n = %d, want n >= 1", ErrInvalidParameter, n)
+	}
+	if err := checkPositive("Zipf s", s); err != nil {
+		return Zipf{}, err
+	}
+	d := Zipf{n: n, s: s}
+	d.hx1 = d.hIntegral(1.5) - 1
+	d.hn = d.hIntegral(float64(n) + 0.5)
+	d.accept = 2 - d.hIntegralInverse(d.hIntegral(2.5)-d.h(2))
+	return d, nil
+}
+
+// Sample returns a Zipf variate in [1, n]
+func (d Zipf) Sample(r *rand.RNG) int64 {
+	for {
+		u := d.hn + r.Float64()*(d.hx1-d.hn)
+		x := d.hIntegralInverse(u)
+		k := min(max(math.Floor(x+0.5), 1), float64(d.n))
+		if k-x <= d.accept || u >= d.hIntegral(k+0.5)-d.h(k) {
+			return int64(k)
+		}
+	}
+}
+
+// h is the unnormalized density x^-s
+func (d Zipf) h(x float64) float64 {
+	return math.Exp(-d.s * math.Log(x))
+}
+
+// hIntegral is an antiderivative of h, (x^(1-s) - 1) / (1-s)
+func (d Zipf) hIntegral(x float64) float64 {
+	lx := math.Log(x)
+	return expm1x((1-d.s)*lx) * lx
+}
+
+// hIntegralInverse is the inverse of hIntegral
+func (d Zipf) hIntegralInverse(x float64) float64 {
+	t := max(x*(1-d.s), -1)
+	return math.Exp(log1px(t) * x)
+}
+
+// expm1x returns expm1(x)/x, continuous at 0
+func expm1x(x float64) float64 {
+	if math.Abs(x) > 1e-8 {
+		return math.Expm1(x) / x
+	}
+	return 1 + x/2*(1+x/3*(1+x/4))
+}
+
+// log1px returns log1p(x)/x, continuous at 0
+func log1px(x float64) float64 {
+	if math.Abs(x) > 1e-8 {
+		return math.Log1p(x) / x
+	}
+	return 1 - x*(1.0/2-x*(1.0/3-x/4))
+}
+
+// Fill fills dst with Zipf variates
+func (d Zipf) Fill(r *rand.RNG, dst []int64) {
+	fill(d, r, dst)
+}
+
+// Zeta is the distribution over the positive integers with P(k)
+// proportional to k^-s, the Zipf distribution with n = infinity
+// Sampled in O(1) expected time by the rejection method of L. Devroye,
+// "Non-Uniform Random Variate Generation", 1986, section X.6.1. Results
+// beyond math.MaxInt64 are rejected.
+type Zeta struct {
+	sm1, b float64 // s-1, 2^(s-1)
+}
+
+var _ Discrete = Zeta{}
+
+// NewZeta returns a zeta distribution
+// s must be finite and > 1.
+func NewZeta(s float64) (Zeta, error) {
+	if err := checkFinite("zeta s", s); err != nil || !(s > 1) {
+		return Zeta{}, fmt.Errorf("%w: zeta s = %v, want a finite value > 1", ErrInvalidParameter, s)
+	}
+	return Zeta{sm1: s - 1, b: math.Exp2(s - 1)}, nil
+}
+
+// Sample returns a zeta variate
+func (d Zeta) Sample(r *rand.RNG) int64 {
+	for {
+		u := r.Float64Open()
+		v := r.Float64()
+		x := math.Floor(math.Pow(u, -1/d.sm1))
+		if x >= math.MaxInt64 || x < 1 {
+			continue
+		}
+		t := math.Pow(1+1/x, d.sm1)
+		if v*x*(t-1)/(d.b-1) <= t/d.b {
+			return int64(x)
+		}
+	}
+}
+
+// Fill fills dst with zeta variates
+func (d Zeta) Fill(r *rand.RNG, dst []int64) {
+	fill(d, r, dst)
+}
@@ -0,0 +1,165 @@
+package rand
+
+import (
+	"math/bits"
+	"unsafe"
+)
+
+// This file contains the bulk methods, which fill a slice with exactly the
+// values the scalar methods would return, in the same order and leaving
+// the generator in the same state.
+//
+// FillUint64 produces the four mixed words of each step() in one unrolled
+// pass instead of one Uint64 call per word. The other methods convert
+// blocks of those words; samplers that can use more than one word per
+// value (IntN with rejection, the ziggurat's slow path) read further words
+// from the same block through a wordBuf, so the consumption order is the
+// same as the scalar loop.
+
+// wordSource is anything that supplies Uint64 outputs in stream order
+type wordSource interface {
+	Uint64() uint64
+}
+
+// FillUint64 fills dst with Uint64 values
+func (r *RNG) FillUint64(dst []uint64) {
+	i := 0
+	// Finish the current step's words
+	for ; i < len(dst) && r.pos < 4; i++ {
+		dst[i] = r.Uint64()
+	}
+
+	// Whole steps; pos stays 4 as every word is consumed
+	if blocks := (len(dst) - i) / 4; blocks > 0 && stepMixBlocks(&r.state, unsafe.Pointer(&dst[i]), blocks) {
+		i += 4 * blocks
+	}
+	for ; len(dst)-i >= 4; i += 4 {
+		r.step()
+		d := dst[i : i+4 : i+4]
+		d[0] = mix(r.state[0])
+		d[1] = mix(r.state[1])
+		d[2] = mix(r.state[2])
+		d[3] = mix(r.state[3])
+	}
+
+	for ; i < len(dst); i++ {
+		dst[i] = r.Uint64()
+	}
+}
+
+// fillChunk is the number of words converted per FillUint64 call
+const fillChunk = 256
+
+// FillFloat64 fills dst with Float64 values
+func (r *RNG) FillFloat64(dst []float64) {
+	var buf [fillChunk]uint64
+	for len(dst) > 0 {
+		w := buf[:min(len(dst), fillChunk)]
+		r.FillUint64(w)
+		for i, u := range w {
+			dst[i] = wordFloat64(u)
+		}
+		dst = dst[len(w):]
+	}
+}
+
+// FillFloat32 fills dst with Float32 values
+func (r *RNG) FillFloat32(dst []float32) {
+	var buf [fillChunk]uint64
+	for len(dst) > 0 {
+		w := buf[:min(len(dst), fillChunk)]
+		r.FillUint64(w)
+		for i, u := range w {
+			dst[i] = wordFloat32(u)
+		}
+		dst = dst[len(w):]
+	}
+}
+
+// FillNormal fills dst with NormFloat64 values
+func (r *RNG) FillNormal(dst []float64) {
+	if r.version == V1 {
+		for i := range dst {
+			dst[i] = r.NormFloat64()
+		}
+		return
+	}
+	b := wordBuf{r: r}
+	for i := range dst {
+		b.later = len(dst) - i - 1
+		for {
+			u := b.Uint64()
+			if x, ok := normFast(u); ok {
+				dst[i] = x
+				break
+			}
+			if x, ok := normSlow(u, &b); ok {
+				dst[i] = x
+				break
+			}
+		}
+	}
+}
+
+// FillIntN fills dst with IntN(n) values
+// Panics if n <= 0
+func (r *RNG) FillIntN(dst []int, n int) {
+	if n <= 0 {
+		panic("invalid argument to FillIntN")
+	}
+	un := uint64(n)
+	if un&(un-1) == 0 {
+		var buf [fillChunk]uint64
+		for len(dst) > 0 {
+			w := buf[:min(len(dst), fillChunk)]
+			r.FillUint64(w)
+			for i, u := range w {
+				dst[i] = int(u & (un - 1))
+			}
+			dst = dst[len(w):]
+		}
+		return
+	}
+	// Lemire's method, as in uint64n
+	thresh := -un % un
+	b := wordBuf{r: r}
+	for i := range dst {
+		b.later = len(dst) - i - 1
+		hi, lo := bits.Mul64(b.Uint64(), un)
+		for lo < thresh {
+			hi, lo = bits.Mul64(b.Uint64(), un)
+		}
+		dst[i] = int(hi)
+	}
+}
+
+// wordBuf hands out Uint64 outputs drawn ahead in blocks with FillUint64
+// It never draws a word the scalar code wouldn't: each refill is capped at
+// one word for the value in progress plus one per value still to come
+// (later), since every value needs at least one word.
+type wordBuf struct {
+	r     *RNG
+	buf   [fillChunk]uint64
+	i, n  int
+	later int // values after the current one
+}
+
+// Uint64 returns the next word of the stream
+func (b *wordBuf) Uint64() uint64 {
+	if b.i == b.n {
+		b.refill()
+	}
+	u := b.buf[b.i]
+	b.i++
+	return u
+}
+
+// refill draws the next block of words
+// Kept out of line so that Uint64 inlines.
+//
+//go:noinline
+func (b *wordBuf) refill() {
+	b.n = min(b.later+1, fillChunk)
+	b.i = 0
+	b.r.FillUint64(b.buf[:b.n])
+}
@@ -0,0 +1,94 @@
+package rand
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+// fillCases runs check for generators at every position within a step,
+// with several slice lengths
+func fillCases(t *testing.T, check func(t *testing.T, a, b *RNG, n int)) {
+	for skip := 0; skip < 4; skip++ {
+		for _, n := range []int{0, 1, 3, 4, 5, 17, 1000, 4099} {
+			a, b := New(77), New(77)
+			a.Advance(uint64(skip))
+			b.Advance(uint64(skip))
+			check(t, a, b, n)
+			if a.Uint64() != b.Uint64() || a.pos != b.pos {
+				t.Fatalf("skip %d, n %d: generators diverge after the fill", skip, n)
+			}
+		}
+	}
+}
+
+func TestFillUint64(t *testing.T) {
+	fillCases(t, func(t *testing.T, a, b *RNG, n int) {
+		got := make([]uint64, n)
+		a.FillUint64(got)
+		for i := range got {
+			if want := b.Uint64(); got[i] != want {
+				t.Fatalf("n %d: FillUint64[%d] = %#x, want %#x", n, i, got[i], want)
+			}
+		}
+	})
+}
+
+func TestFillFloat(t *testing.T) {
+	fillCases(t, func(t *testing.T, a, b *RNG, n int) {
+		got := make([]float64, n)
+		a.FillFloat64(got)
+		got32 := make([]float32, n)
+		a.FillFloat32(got32)
+		for i := range got {
+			if want := b.Float64(); got[i] != want {
+				t.Fatalf("n %d: FillFloat64[%d] = %v, want %v", n, i, got[i], want)
+			}
+		}
+		for i := range got32 {
+			if want := b.Float32(); got32[i] != want {
+				t.Fatalf("n %d: FillFloat32[%d] = %v, want %v", n, i, got32[i], want)
+			}
+		}
+	})
+}
+
+func TestFillNormal(t *testing.T) {
+	// Long fills hit the ziggurat's slow paths, which take extra words
+	for _, v := range []Version{V1, V2} {
+		fillCases(t, func(t *testing.T, a, b *RNG, n int) {
+			a.SetVersion(v)
+			b.SetVersion(v)
+			got := make([]float64, n*10)
+			a.FillNormal(got)
+			for i := range got {
+				if want := b.NormFloat64(); got[i] != want {
+					t.Fatalf("%v, n %d: FillNormal[%d] = %v, want %v", v, len(got), i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestFillIntN(t *testing.T) {
+	// On 64-bit, MaxInt/4 + 1 rejects about a quarter of the words
+	for _, bound := range []int{1, 6, 8, 1000, math.MaxInt/3 + 7, math.MaxInt/4 + 1} {
+		fillCases(t, func(t *testing.T, a, b *RNG, n int) {
+			got := make([]int, n)
+			a.FillIntN(got, bound)
+			want := make([]int, n)
+			for i := range want {
+				want[i] = b.IntN(bound)
+			}
+			if !slices.Equal(got, want) {
+				t.Fatalf("bound %d, n %d: FillIntN differs from IntN", bound, n)
+			}
+		})
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("FillIntN(0) did not panic")
+		}
+	}()
+	New(1).FillIntN(make([]int, 1), 0)
+}
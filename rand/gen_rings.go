@@ -0,0 +1,118 @@
+//go:build ignore
+
+// gen_rings generates rings_gen.go: Rule 30 generators for ring widths
+// other than RNG's 256 bits, with the step fully unrolled as in RNG.
+//
+// Usage: go generate ./rand
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"text/template"
+)
+
+// widths lists the generated ring sizes in 64-bit words
+var widths = []int{2, 8, 16}
+
+type ring struct {
+	Name  string
+	Bits  int
+	Words int
+}
+
+// Prev and Next give the ring neighbours of word i
+func (r ring) Prev(i int) int { return (i + r.Words - 1) % r.Words }
+func (r ring) Next(i int) int { return (i + 1) % r.Words }
+
+// Range returns 0..Words-1 for template loops
+func (r ring) Range() []int {
+	out := make([]int, r.Words)
+	for i := range out {
+		out[i] = i
+	}
+	return out
+}
+
+var tmpl = template.Must(template.New("rings").Parse(`// Code generated by gen_rings.go; DO NOT EDIT.
+
+package rand
+{{range $r := .}}
+// {{.Name}} runs Rule 30 on a {{.Bits}}-bit ring ({{.Words}} × 64-bit words)
+type {{.Name}} struct {
+	state [{{.Words}}]uint64
+	pos   int
+	tail  uint64
+	ntail int
+}
+
+// New{{.Name}} creates a new {{.Bits}}-bit Rule 30 generator from a seed
+func New{{.Name}}(seed uint64) *{{.Name}} {
+	r := &{{.Name}}{}
+	r.Reseed(seed)
+	return r
+}
+
+// Reseed resets the generator to the state New{{.Name}}(seed) would produce
+func (r *{{.Name}}) Reseed(seed uint64) {
+	for i := range r.state {
+		r.state[i] = seed ^ ringSeedConst(i)
+	}
+	r.pos = 0
+	r.tail, r.ntail = 0, 0
+	for i := 0; i < 4*len(r.state); i++ {
+		r.step()
+	}
+}
+
+// step applies radius-1 Rule 30 to the {{.Bits}}-bit ring
+//
+//go:noinline
+func (r *{{.Name}}) step() {
+	{{range $i := .Range}}s{{$i}} := r.state[{{$i}}]
+	{{end}}
+	{{range $i := .Range}}b0_{{$i}}, b63_{{$i}} := s{{$i}}&1, s{{$i}}>>63
+	{{end}}
+	{{range $i := .Range}}r.state[{{$i}}] = ((s{{$i}} >> 1) | (b0_{{$r.Prev $i}} << 63)) ^ (s{{$i}} | ((s{{$i}} << 1) | b63_{{$r.Next $i}}))
+	{{end -}}
+}
+
+// Uint64 returns a random uint64
+func (r *{{.Name}}) Uint64() uint64 {
+	if r.pos == len(r.state) {
+		r.step()
+		r.pos = 0
+	}
+	out := mix(r.state[r.pos])
+	r.pos++
+	return out
+}
+
+// Read implements io.Reader like RNG.Read
+func (r *{{.Name}}) Read(buf []byte) (n int, err error) {
+	readWords(buf, &r.tail, &r.ntail, r.Uint64, nil)
+	return len(buf), nil
+}
+{{end}}`))
+
+func main() {
+	var rings []ring
+	for _, w := range widths {
+		rings = append(rings, ring{Name: fmt.Sprintf("Ring%d", 64*w), Bits: 64 * w, Words: w})
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, rings); err != nil {
+		log.Fatal(err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("formatting generated code: %v\n%s", err, buf.Bytes())
+	}
+	if err := os.WriteFile("rings_gen.go", src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,91 @@
+// Package geom samples random points and vectors using a ring30mix
+// generator: uniform points on and in circles, spheres and balls of any
+// dimension, in triangles and simplices, Dirichlet vectors, random
+// rotations, and correlated normal vectors.
+//
+// Fixed-size results use the small array types Vec2 and Vec3; results of
+// any dimension are written into a caller-provided slice, whose length
+// sets the dimension. Functions panic on mismatched lengths, like the
+// slice helpers in package rand; constructors that take parameters return
+// an error wrapping dist.ErrInvalidParameter.
+package geom
+
+import (
+	"math"
+
+	"github.com/vrypan/ring30mix/rand"
+)
+
+// Vec2 is a point or vector in the plane
+type Vec2 [2]float64
+
+// Vec3 is a point or vector in space
+type Vec3 [3]float64
+
+// OnCircle returns a uniform point on the unit circle
+func OnCircle(r *rand.RNG) Vec2 {
+	s, c := math.Sincos(2 * math.Pi * r.Float64())
+	return Vec2{c, s}
+}
+
+// InDisk returns a uniform point in the unit disk
+func InDisk(r *rand.RNG) Vec2 {
+	// The area within radius rho grows as rho^2
+	rho := math.Sqrt(r.Float64())
+	p := OnCircle(r)
+	return Vec2{rho * p[0], rho * p[1]}
+}
+
+// OnSphere returns a uniform point on the unit sphere in 3D
+// By Archimedes' hat-box theorem the height is uniform in [-1, 1].
+func OnSphere(r *rand.RNG) Vec3 {
+	z := 2*r.Float64() - 1
+	rho := math.Sqrt(1 - z*z)
+	s, c := math.Sincos(2 * math.Pi * r.Float64())
+	return Vec3{rho * c, rho * s, z}
+}
+
+// InBall returns a uniform point in the unit ball in 3D
+func InBall(r *rand.RNG) Vec3 {
+	rho := math.Cbrt(r.Float64())
+	p := OnSphere(r)
+	return Vec3{rho * p[0], rho * p[1], rho * p[2]}
+}
+
+// OnNSphere stores a uniform point on the unit sphere in len(dst)
+// dimensions in dst, by normalizing a vector of standard normals
+// Panics if dst is empty
+func OnNSphere(r *rand.RNG, dst []float64) {
+	if len(dst) == 0 {
+		panic("invalid argument to OnNSphere")
+	}
+	for {
+		var norm float64
+		for i := range dst {
+			dst[i] = r.NormFloat64()
+			norm += dst[i] * dst[i]
+		}
+		if norm > 0 {
+			scale(dst, 1/math.Sqrt(norm))
+			return
+		}
+	}
+}
+
+// InNBall stores a uniform point in the unit ball in len(dst) dimensions
+// in dst
+// Panics if dst is empty
+func InNBall(r *rand.RNG, dst []float64) {
+	if len(dst) == 0 {
+		panic("invalid argument to InNBall")
+	}
+	OnNSphere(r, dst)
+	scale(dst, math.Pow(r.Float64(), 1/float64(len(dst))))
+}
+
+// scale multiplies v by s in place
+func scale(v []float64, s float64) {
+	for i := range v {
+		v[i] *= s
+	}
+}
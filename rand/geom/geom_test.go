@@ -0,0 +1,154 @@
+package geom
+
+import (
+	"math"
+	"testing"
+
+	"github.com/vrypan/ring30mix/rand"
+)
+
+const (
+	samples = 200_000
+	bins    = 64
+	// Chi-square critical value for 63 degrees of freedom, p = 0.001
+	critical = 103.4
+)
+
+// checkUniform fails if values, expected uniform in [0, 1), don't pass a
+// chi-square test on equal bins
+func checkUniform(t *testing.T, name string, values []float64) {
+	t.Helper()
+	counts := make([]int, bins)
+	for _, v := range values {
+		if !(v >= 0 && v <= 1) {
+			t.Fatalf("%s: value %v outside [0, 1]", name, v)
+		}
+		counts[min(int(v*bins), bins-1)]++
+	}
+	e := float64(len(values)) / bins
+	var x2 float64
+	for _, c := range counts {
+		d := float64(c) - e
+		x2 += d * d / e
+	}
+	if x2 > critical {
+		t.Errorf("%s: chi-square = %.1f, want <= %.1f", name, x2, critical)
+	}
+}
+
+// angle01 maps the angle of (x, y) to [0, 1)
+func angle01(x, y float64) float64 {
+	return (math.Atan2(y, x) + math.Pi) / (2 * math.Pi)
+}
+
+func norm(v []float64) float64 {
+	var s float64
+	for _, x := range v {
+		s += x * x
+	}
+	return math.Sqrt(s)
+}
+
+func TestCircleAndDisk(t *testing.T) {
+	r := rand.New(1)
+	onAngle := make([]float64, samples)
+	inAngle := make([]float64, samples)
+	inRadius := make([]float64, samples)
+	for i := range onAngle {
+		p := OnCircle(r)
+		if math.Abs(norm(p[:])-1) > 1e-12 {
+			t.Fatalf("OnCircle point %v not on the circle", p)
+		}
+		onAngle[i] = angle01(p[0], p[1])
+		q := InDisk(r)
+		rho := norm(q[:])
+		inAngle[i] = angle01(q[0], q[1])
+		inRadius[i] = rho * rho
+	}
+	checkUniform(t, "OnCircle angle", onAngle)
+	checkUniform(t, "InDisk angle", inAngle)
+	checkUniform(t, "InDisk radius^2", inRadius)
+}
+
+func TestSphereAndBall(t *testing.T) {
+	r := rand.New(2)
+	height := make([]float64, samples)
+	azimuth := make([]float64, samples)
+	radius := make([]float64, samples)
+	for i := range height {
+		p := OnSphere(r)
+		if math.Abs(norm(p[:])-1) > 1e-12 {
+			t.Fatalf("OnSphere point %v not on the sphere", p)
+		}
+		height[i] = (p[2] + 1) / 2
+		azimuth[i] = angle01(p[0], p[1])
+		q := InBall(r)
+		rho := norm(q[:])
+		radius[i] = rho * rho * rho
+	}
+	checkUniform(t, "OnSphere height", height)
+	checkUniform(t, "OnSphere azimuth", azimuth)
+	checkUniform(t, "InBall radius^3", radius)
+}
+
+func TestNSphereAndNBall(t *testing.T) {
+	// On the unit n-sphere E[x^2] = 1/n and E[x^4] = 3/(n(n+2)) for every
+	// coordinate; in the unit n-ball ||x||^n is uniform
+	const n = 5
+	r := rand.New(3)
+	v := make([]float64, n)
+	var m2, m4 [n]float64
+	radius := make([]float64, samples)
+	for i := 0; i < samples; i++ {
+		OnNSphere(r, v)
+		if math.Abs(norm(v)-1) > 1e-12 {
+			t.Fatalf("OnNSphere point %v not on the sphere", v)
+		}
+		for k, x := range v {
+			m2[k] += x * x
+			m4[k] += x * x * x * x
+		}
+		InNBall(r, v)
+		radius[i] = math.Pow(norm(v), n)
+	}
+	for k := range v {
+		if got := m2[k] / samples; math.Abs(got-1.0/n) > 0.003 {
+			t.Errorf("coordinate %d: E[x^2] = %.4f, want %.4f", k, got, 1.0/n)
+		}
+		if got, want := m4[k]/samples, 3.0/(n*(n+2)); math.Abs(got-want) > 0.002 {
+			t.Errorf("coordinate %d: E[x^4] = %.4f, want %.4f", k, got, want)
+		}
+	}
+	checkUniform(t, "InNBall radius^n", radius)
+}
+
+func TestRotationQuat(t *testing.T) {
+	// A uniform rotation sends any fixed vector to a uniform point on the
+	// sphere
+	r := rand.New(4)
+	height := make([]float64, samples)
+	azimuth := make([]float64, samples)
+	for i := range height {
+		q := RotationQuat(r)
+		if n := math.Sqrt(q.W*q.W + q.X*q.X + q.Y*q.Y + q.Z*q.Z); math.Abs(n-1) > 1e-12 {
+			t.Fatalf("quaternion %v has norm %v", q, n)
+		}
+		p := q.Rotate(Vec3{0.6, 0, 0.8})
+		if math.Abs(norm(p[:])-1) > 1e-12 {
+			t.Fatalf("rotation changed the length: %v", p)
+		}
+		height[i] = (p[2] + 1) / 2
+		azimuth[i] = angle01(p[0], p[1])
+	}
+	checkUniform(t, "rotated height", height)
+	checkUniform(t, "rotated azimuth", azimuth)
+}
+
+func TestRotate(t *testing.T) {
+	// 90 degrees about z sends x to y
+	h := math.Sqrt(0.5)
+	got := Quat{W: h, Z: h}.Rotate(Vec3{1, 0, 0})
+	if math.Abs(got[0]) > 1e-15 || math.Abs(got[1]-1) > 1e-15 || got[2] != 0 {
+		t.Fatalf("Rotate = %v, want [0 1 0]", got)
+	}
+}
@@ -0,0 +1,97 @@
+package geom
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/vrypan/ring30mix/rand"
+	"github.com/vrypan/ring30mix/rand/dist"
+)
+
+// This file contains the multivariate normal distribution.
+
+// MultiNormal is the multivariate normal distribution with a mean vector
+// and a covariance matrix
+// Samples are mean + L*z for standard normals z, where L is the Cholesky
+// factor of the covariance (covariance = L*L^T).
+type MultiNormal struct {
+	mean []float64
+	l    [][]float64 // lower triangular, row i has i+1 entries
+}
+
+// NewMultiNormal returns a multivariate normal distribution
+// cov must be a len(mean) x len(mean) symmetric positive definite matrix
+// of finite values. mean and cov are copied.
+func NewMultiNormal(mean []float64, cov [][]float64) (*MultiNormal, error) {
+	n := len(mean)
+	if n == 0 || len(cov) != n {
+		return nil, fmt.Errorf("%w: mean has %d entries, covariance %d rows", dist.ErrInvalidParameter, n, len(cov))
+	}
+	// Check the shape first: the symmetry test below reads cov[j][i]
+	for i, row := range cov {
+		if len(row) != n {
+			return nil, fmt.Errorf("%w: covariance row %d has %d entries, want %d", dist.ErrInvalidParameter, i, len(row), n)
+		}
+	}
+	for i, row := range cov {
+		for j, v := range row {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return nil, fmt.Errorf("%w: covariance[%d][%d] = %v", dist.ErrInvalidParameter, i, j, v)
+			}
+			if d := v - cov[j][i]; math.Abs(d) > 1e-12*max(math.Abs(v), 1) {
+				return nil, fmt.Errorf("%w: covariance is not symmetric at [%d][%d]", dist.ErrInvalidParameter, i, j)
+			}
+		}
+	}
+	for i, m := range mean {
+		if math.IsNaN(m) || math.IsInf(m, 0) {
+			return nil, fmt.Errorf("%w: mean[%d] = %v", dist.ErrInvalidParameter, i, m)
+		}
+	}
+
+	// Cholesky-Banachiewicz, row by row
+	l := make([][]float64, n)
+	for i := range l {
+		l[i] = make([]float64, i+1)
+		for j := 0; j <= i; j++ {
+			sum := cov[i][j]
+			for k := 0; k < j; k++ {
+				sum -= l[i][k] * l[j][k]
+			}
+			if i == j {
+				if !(sum > 0) {
+					return nil, fmt.Errorf("%w: covariance is not positive definite", dist.ErrInvalidParameter)
+				}
+				l[i][i] = math.Sqrt(sum)
+			} else {
+				l[i][j] = sum / l[j][j]
+			}
+		}
+	}
+	return &MultiNormal{mean: append([]float64(nil), mean...), l: l}, nil
+}
+
+// Dim returns the number of dimensions
+func (d *MultiNormal) Dim() int {
+	return len(d.mean)
+}
+
+// Sample stores a multivariate normal variate in dst
+// Panics if len(dst) != Dim()
+func (d *MultiNormal) Sample(r *rand.RNG, dst []float64) {
+	if len(dst) != len(d.mean) {
+		panic("invalid argument to Sample")
+	}
+	// Fill dst with z first; row i of L only needs z[0..i], so compute
+	// from the last row down and overwrite in place
+	for i := range dst {
+		dst[i] = r.NormFloat64()
+	}
+	for i := len(dst) - 1; i >= 0; i-- {
+		sum := d.mean[i]
+		for k, lik := range d.l[i] {
+			sum += lik * dst[k]
+		}
+		dst[i] = sum
+	}
+}
@@ -0,0 +1,72 @@
+package geom
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/vrypan/ring30mix/rand"
+	"github.com/vrypan/ring30mix/rand/dist"
+)
+
+func TestMultiNormal(t *testing.T) {
+	mean := []float64{1, -2, 0.5}
+	cov := [][]float64{
+		{4, 1.2, -0.6},
+		{1.2, 1, 0.3},
+		{-0.6, 0.3, 0.6},
+	}
+	d, err := NewMultiNormal(mean, cov)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := rand.New(10)
+	v := make([]float64, d.Dim())
+	var sum [3]float64
+	var prod [3][3]float64
+	for i := 0; i < samples; i++ {
+		d.Sample(r, v)
+		for a := range v {
+			sum[a] += v[a]
+			for b := range v {
+				prod[a][b] += v[a] * v[b]
+			}
+		}
+	}
+	for a := range mean {
+		m := sum[a] / samples
+		if math.Abs(m-mean[a]) > 5*math.Sqrt(cov[a][a]/samples) {
+			t.Errorf("mean[%d] = %.4f, want %.4f", a, m, mean[a])
+		}
+		for b := range mean {
+			got := prod[a][b]/samples - m*sum[b]/samples
+			// The sample covariance has a standard error of about
+			// sqrt((s_aa s_bb + s_ab^2) / n)
+			se := math.Sqrt((cov[a][a]*cov[b][b] + cov[a][b]*cov[a][b]) / samples)
+			if math.Abs(got-cov[a][b]) > 5*se {
+				t.Errorf("cov[%d][%d] = %.4f, want %.4f", a, b, got, cov[a][b])
+			}
+		}
+	}
+}
+
+func TestMultiNormalRejects(t *testing.T) {
+	cases := []struct {
+		mean []float64
+		cov  [][]float64
+	}{
+		{nil, nil},
+		{[]float64{0, 0}, [][]float64{{1, 0}}},
+		{[]float64{0, 0}, [][]float64{{1, 0}, {}}},         // ragged
+		{[]float64{0, 0}, [][]float64{{1, 0}, {0, 1, 0}}},  // ragged
+		{[]float64{0, 0}, [][]float64{{1, 0.5}, {0.4, 1}}}, // not symmetric
+		{[]float64{0, 0}, [][]float64{{1, 2}, {2, 1}}},     // indefinite
+		{[]float64{0, 0}, [][]float64{{1, 1}, {1, 1}}},     // singular
+		{[]float64{math.NaN()}, [][]float64{{1}}},
+	}
+	for i, c := range cases {
+		if _, err := NewMultiNormal(c.mean, c.cov); !errors.Is(err, dist.ErrInvalidParameter) {
+			t.Errorf("case %d: err = %v", i, err)
+		}
+	}
+}
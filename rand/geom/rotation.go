@@ -0,0 +1,47 @@
+package geom
+
+import (
+	"math"
+
+	"github.com/vrypan/ring30mix/rand"
+)
+
+// This file contains uniformly random rotations.
+
+// Quat is a quaternion W + Xi + Yj + Zk
+type Quat struct {
+	W, X, Y, Z float64
+}
+
+// RotationQuat returns a unit quaternion for a uniformly random rotation
+// Uses K. Shoemake, "Uniform Random Rotations", Graphics Gems III, 1992.
+// q and -q are the same rotation; both are equally likely.
+func RotationQuat(r *rand.RNG) Quat {
+	u1 := r.Float64()
+	s1, c1 := math.Sincos(2 * math.Pi * r.Float64())
+	s2, c2 := math.Sincos(2 * math.Pi * r.Float64())
+	a, b := math.Sqrt(1-u1), math.Sqrt(u1)
+	return Quat{W: b * c2, X: a * s1, Y: a * c1, Z: b * s2}
+}
+
+// Rotate returns v rotated by the unit quaternion q
+func (q Quat) Rotate(v Vec3) Vec3 {
+	// v' = v + 2w(u × v) + 2u × (u × v), with u the vector part of q
+	u := Vec3{q.X, q.Y, q.Z}
+	t := cross(u, v)
+	t = Vec3{2 * t[0], 2 * t[1], 2 * t[2]}
+	c := cross(u, t)
+	return Vec3{
+		v[0] + q.W*t[0] + c[0],
+		v[1] + q.W*t[1] + c[1],
+		v[2] + q.W*t[2] + c[2],
+	}
+}
+
+func cross(a, b Vec3) Vec3 {
+	return Vec3{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
@@ -0,0 +1,150 @@
+package geom
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/vrypan/ring30mix/rand"
+	"github.com/vrypan/ring30mix/rand/dist"
+)
+
+// This file contains sampling from triangles, simplices and the Dirichlet
+// distribution, which all come down to random barycentric weights.
+
+// triangleWeights returns uniform barycentric weights for a triangle,
+// folding the unit square onto the lower triangle
+func triangleWeights(r *rand.RNG) (wa, wb, wc float64) {
+	u, v := r.Float64(), r.Float64()
+	if u+v > 1 {
+		u, v = 1-u, 1-v
+	}
+	return 1 - u - v, u, v
+}
+
+// InTriangle returns a uniform point in the triangle abc
+func InTriangle(r *rand.RNG, a, b, c Vec2) Vec2 {
+	wa, wb, wc := triangleWeights(r)
+	return Vec2{
+		wa*a[0] + wb*b[0] + wc*c[0],
+		wa*a[1] + wb*b[1] + wc*c[1],
+	}
+}
+
+// InTriangle3 returns a uniform point in the triangle abc in space
+func InTriangle3(r *rand.RNG, a, b, c Vec3) Vec3 {
+	wa, wb, wc := triangleWeights(r)
+	return Vec3{
+		wa*a[0] + wb*b[0] + wc*c[0],
+		wa*a[1] + wb*b[1] + wc*c[1],
+		wa*a[2] + wb*b[2] + wc*c[2],
+	}
+}
+
+// OnSimplex stores a uniform point on the standard simplex (non-negative
+// coordinates summing to 1) in dst
+// Equivalent to Dirichlet(1, ..., 1): normalized exponential variates.
+// Panics if dst is empty
+func OnSimplex(r *rand.RNG, dst []float64) {
+	if len(dst) == 0 {
+		panic("invalid argument to OnSimplex")
+	}
+	for {
+		var sum float64
+		for i := range dst {
+			dst[i] = r.ExpFloat64()
+			sum += dst[i]
+		}
+		if sum > 0 {
+			scale(dst, 1/sum)
+			return
+		}
+	}
+}
+
+// InSimplex stores a uniform point in the simplex spanned by vertices in
+// dst
+// Every vertex must have len(dst) coordinates; any number of vertices is
+// allowed, and the point is uniform in their simplex when they are
+// affinely independent.
+// Panics if there are no vertices or their lengths differ from len(dst)
+func InSimplex(r *rand.RNG, vertices [][]float64, dst []float64) {
+	if len(vertices) == 0 {
+		panic("invalid argument to InSimplex")
+	}
+	for _, v := range vertices {
+		if len(v) != len(dst) {
+			panic("invalid argument to InSimplex")
+		}
+	}
+	w := make([]float64, len(vertices))
+	OnSimplex(r, w)
+	clear(dst)
+	for k, v := range vertices {
+		for i := range dst {
+			dst[i] += w[k] * v[i]
+		}
+	}
+}
+
+// Dirichlet is the Dirichlet distribution over the simplex with
+// concentration parameters alpha
+// Sampled by normalizing independent Gamma(alpha_i, 1) variates, kept as
+// logarithms: for alpha_i < 1 the variate is Gamma(alpha_i+1) * U^(1/alpha_i),
+// as in dist.Gamma, which underflows for tiny alphas while its logarithm
+// doesn't. The components are then normalized with log-sum-exp.
+type Dirichlet struct {
+	gammas []dist.Gamma // Gamma(alpha_i+1) when alpha_i < 1
+	boost  []float64    // 1/alpha_i when alpha_i < 1, else 0
+}
+
+// NewDirichlet returns a Dirichlet distribution
+// There must be at least one alpha, each finite and > 0.
+func NewDirichlet(alpha []float64) (*Dirichlet, error) {
+	if len(alpha) == 0 {
+		return nil, fmt.Errorf("%w: Dirichlet needs at least one alpha", dist.ErrInvalidParameter)
+	}
+	d := &Dirichlet{
+		gammas: make([]dist.Gamma, len(alpha)),
+		boost:  make([]float64, len(alpha)),
+	}
+	for i, a := range alpha {
+		g, err := dist.NewGamma(a, 1)
+		if err != nil {
+			return nil, fmt.Errorf("Dirichlet alpha %d: %w", i, err)
+		}
+		if a < 1 {
+			g, _ = dist.NewGamma(a+1, 1)
+			d.boost[i] = 1 / a
+		}
+		d.gammas[i] = g
+	}
+	return d, nil
+}
+
+// Dim returns the number of components
+func (d *Dirichlet) Dim() int {
+	return len(d.gammas)
+}
+
+// Sample stores a Dirichlet variate in dst
+// Panics if len(dst) != Dim()
+func (d *Dirichlet) Sample(r *rand.RNG, dst []float64) {
+	if len(dst) != len(d.gammas) {
+		panic("invalid argument to Sample")
+	}
+	// dst holds the log-gamma variates until they are normalized
+	m := math.Inf(-1)
+	for i, g := range d.gammas {
+		dst[i] = math.Log(g.Sample(r))
+		if d.boost[i] != 0 {
+			dst[i] += math.Log(r.Float64Open()) * d.boost[i]
+		}
+		m = max(m, dst[i])
+	}
+	var sum float64
+	for i, l := range dst {
+		dst[i] = math.Exp(l - m)
+		sum += dst[i]
+	}
+	scale(dst, 1/sum)
+}
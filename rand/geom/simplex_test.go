@@ -0,0 +1,172 @@
+package geom
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/vrypan/ring30mix/rand"
+	"github.com/vrypan/ring30mix/rand/dist"
+)
+
+func TestInTriangle(t *testing.T) {
+	// The midpoints split the triangle into four of equal area; the
+	// points must fall evenly into them (3 degrees of freedom, p = 0.001)
+	a, b, c := Vec2{0, 0}, Vec2{4, 0}, Vec2{1, 3}
+	r := rand.New(5)
+	var counts [4]int
+	for i := 0; i < samples; i++ {
+		p := InTriangle(r, a, b, c)
+		// Barycentric coordinates of p
+		det := (b[1]-c[1])*(a[0]-c[0]) + (c[0]-b[0])*(a[1]-c[1])
+		la := ((b[1]-c[1])*(p[0]-c[0]) + (c[0]-b[0])*(p[1]-c[1])) / det
+		lb := ((c[1]-a[1])*(p[0]-c[0]) + (a[0]-c[0])*(p[1]-c[1])) / det
+		lc := 1 - la - lb
+		if la < -1e-12 || lb < -1e-12 || lc < -1e-12 {
+			t.Fatalf("point %v outside the triangle", p)
+		}
+		switch {
+		case la > 0.5:
+			counts[0]++
+		case lb > 0.5:
+			counts[1]++
+		case lc > 0.5:
+			counts[2]++
+		default:
+			counts[3]++
+		}
+	}
+	var x2 float64
+	for _, n := range counts {
+		d := float64(n) - samples/4
+		x2 += d * d / (samples / 4)
+	}
+	if x2 > 16.27 {
+		t.Errorf("InTriangle chi-square = %.1f, counts %v", x2, counts)
+	}
+
+	// InTriangle3 uses the same weights
+	p := InTriangle3(rand.New(6), Vec3{0, 0, 1}, Vec3{4, 0, 1}, Vec3{1, 3, 1})
+	q := InTriangle(rand.New(6), a, b, c)
+	if p[0] != q[0] || p[1] != q[1] || p[2] != 1 {
+		t.Fatalf("InTriangle3 = %v, InTriangle = %v", p, q)
+	}
+}
+
+func TestOnSimplex(t *testing.T) {
+	// On the standard simplex in n dimensions each coordinate is
+	// Beta(1, n-1), with CDF 1 - (1-x)^(n-1)
+	const n = 4
+	r := rand.New(7)
+	v := make([]float64, n)
+	cdf := make([]float64, samples)
+	for i := range cdf {
+		OnSimplex(r, v)
+		var sum float64
+		for _, x := range v {
+			if x < 0 {
+				t.Fatalf("negative coordinate in %v", v)
+			}
+			sum += x
+		}
+		if math.Abs(sum-1) > 1e-12 {
+			t.Fatalf("coordinates sum to %v", sum)
+		}
+		cdf[i] = 1 - math.Pow(1-v[2], n-1)
+	}
+	checkUniform(t, "OnSimplex coordinate", cdf)
+}
+
+func TestInSimplex(t *testing.T) {
+	// A tetrahedron: points stay inside and the mean is the centroid
+	vertices := [][]float64{{0, 0, 0}, {2, 0, 0}, {0, 3, 0}, {0, 0, 6}}
+	r := rand.New(8)
+	p := make([]float64, 3)
+	var mean [3]float64
+	for i := 0; i < samples; i++ {
+		InSimplex(r, vertices, p)
+		if p[0] < 0 || p[1] < 0 || p[2] < 0 || p[0]/2+p[1]/3+p[2]/6 > 1+1e-12 {
+			t.Fatalf("point %v outside the tetrahedron", p)
+		}
+		for k := range mean {
+			mean[k] += p[k] / samples
+		}
+	}
+	for k, want := range []float64{0.5, 0.75, 1.5} {
+		if math.Abs(mean[k]-want) > 0.02 {
+			t.Errorf("mean[%d] = %.4f, want %.4f", k, mean[k], want)
+		}
+	}
+}
+
+func TestDirichlet(t *testing.T) {
+	alpha := []float64{0.3, 1, 4.5}
+	d, err := NewDirichlet(alpha)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var a0 float64
+	for _, a := range alpha {
+		a0 += a
+	}
+	r := rand.New(9)
+	v := make([]float64, d.Dim())
+	var sum, sumsq [3]float64
+	for i := 0; i < samples; i++ {
+		d.Sample(r, v)
+		for k, x := range v {
+			sum[k] += x
+			sumsq[k] += x * x
+		}
+	}
+	for k, a := range alpha {
+		mean := sum[k] / samples
+		variance := sumsq[k]/samples - mean*mean
+		wantMean := a / a0
+		wantVar := a * (a0 - a) / (a0 * a0 * (a0 + 1))
+		if math.Abs(mean-wantMean) > 5*math.Sqrt(wantVar/samples) {
+			t.Errorf("component %d: mean %.5f, want %.5f", k, mean, wantMean)
+		}
+		if math.Abs(variance-wantVar) > 0.02*wantVar {
+			t.Errorf("component %d: variance %.5f, want %.5f", k, variance, wantVar)
+		}
+	}
+
+	// Tiny alphas put almost all the mass on one component; the gamma
+	// variates underflow, their logarithms don't
+	tiny := []float64{1e-4, 1e-3, 2e-3}
+	d, err = NewDirichlet(tiny)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a0 = tiny[0] + tiny[1] + tiny[2]
+	sum = [3]float64{}
+	for i := 0; i < samples; i++ {
+		d.Sample(r, v)
+		var total float64
+		for k, x := range v {
+			if !(x >= 0 && x <= 1) {
+				t.Fatalf("tiny alphas: component %d = %v", k, x)
+			}
+			sum[k] += x
+			total += x
+		}
+		if math.Abs(total-1) > 1e-12 {
+			t.Fatalf("tiny alphas: components sum to %v", total)
+		}
+	}
+	for k, a := range tiny {
+		mean := sum[k] / samples
+		wantMean := a / a0
+		wantVar := a * (a0 - a) / (a0 * a0 * (a0 + 1))
+		if math.Abs(mean-wantMean) > 5*math.Sqrt(wantVar/samples) {
+			t.Errorf("tiny alphas: component %d mean %.4f, want %.4f", k, mean, wantMean)
+		}
+	}
+
+	for _, bad := range [][]float64{nil, {1, 0}, {math.NaN()}} {
+		if _, err := NewDirichlet(bad); !errors.Is(err, dist.ErrInvalidParameter) {
+			t.Errorf("NewDirichlet(%v): err = %v", bad, err)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package rand
+
+import (
+	cryptorand "crypto/rand"
+	"sync"
+)
+
+// This file contains the package-level functions, which draw from an
+// automatically seeded default source like math/rand's top-level functions.
+//
+// The default source is a sync.Pool of generators rather than one locked
+// RNG. The pool keeps a cache per P (logical processor), so concurrent
+// callers almost never touch the same generator and throughput scales
+// with GOMAXPROCS. Each generator is seeded with 256 bits from crypto/rand,
+// so the output is not reproducible; use New for deterministic streams.
+
+var globalPool = sync.Pool{New: func() any { return newGlobal() }}
+
+// newGlobal returns a generator with a fresh random 256-bit seed
+func newGlobal() *RNG {
+	for {
+		var key [32]byte
+		cryptorand.Read(key[:])
+		if rng, err := NewFromKey(key); err == nil {
+			return rng
+		}
+	}
+}
+
+func getGlobal() *RNG  { return globalPool.Get().(*RNG) }
+func putGlobal(r *RNG) { globalPool.Put(r) }
+
+// Uint64 returns a random uint64 from the default source
+func Uint64() uint64 {
+	r := getGlobal()
+	v := r.Uint64()
+	putGlobal(r)
+	return v
+}
+
+// Uint32 returns a random uint32 from the default source
+func Uint32() uint32 {
+	r := getGlobal()
+	v := r.Uint32()
+	putGlobal(r)
+	return v
+}
+
+// Uint returns a random uint from the default source
+func Uint() uint {
+	r := getGlobal()
+	v := r.Uint()
+	putGlobal(r)
+	return v
+}
+
+// Int returns a non-negative random int from the default source
+func Int() int {
+	r := getGlobal()
+	v := r.Int()
+	putGlobal(r)
+	return v
+}
+
+// Int63 returns a non-negative random int64 from the default source
+func Int63() int64 {
+	r := getGlobal()
+	v := r.Int63()
+	putGlobal(r)
+	return v
+}
+
+// Int31 returns a non-negative random int32 from the default source
+func Int31() int32 {
+	r := getGlobal()
+	v := r.Int31()
+	putGlobal(r)
+	return v
+}
+
+// Int64 returns a non-negative random int64 from the default source
+func Int64() int64 {
+	r := getGlobal()
+	v := r.Int64()
+	putGlobal(r)
+	return v
+}
+
+// Int32 returns a non-negative random int32 from the default source
+func Int32() int32 {
+	r := getGlobal()
+	v := r.Int32()
+	putGlobal(r)
+	return v
+}
+
+// IntN returns a random int in [0, n) from the default source
+// Panics if n <= 0
+func IntN(n int) int {
+	r := getGlobal()
+	defer putGlobal(r)
+	return r.IntN(n)
+}
+
+// Intn returns a random int in [0, n) from the default source
+// Panics if n <= 0
+func Intn(n int) int {
+	r := getGlobal()
+	defer putGlobal(r)
+	return r.Intn(n)
+}
+
+// Int64N returns a random int64 in [0, n) from the default source
+// Panics if n <= 0
+func Int64N(n int64) int64 {
+	r := getGlobal()
+	defer putGlobal(r)
+	return r.Int64N(n)
+}
+
+// Int63n returns a random int64 in [0, n) from the default source
+// Panics if n <= 0
+func Int63n(n int64) int64 {
+	r := getGlobal()
+	defer putGlobal(r)
+	return r.Int63n(n)
+}
+
+// Int32N returns a random int32 in [0, n) from the default source
+// Panics if n <= 0
+func Int32N(n int32) int32 {
+	r := getGlobal()
+	defer putGlobal(r)
+	return r.Int32N(n)
+}
+
+// Int31n returns a random int32 in [0, n) from the default source
+// Panics if n <= 0
+func Int31n(n int32) int32 {
+	r := getGlobal()
+	defer putGlobal(r)
+	return r.Int31n(n)
+}
+
+// UintN returns a random uint in [0, n) from the default source
+// Panics if n == 0
+func UintN(n uint) uint {
+	r := getGlobal()
+	defer putGlobal(r)
+	return r.UintN(n)
+}
+
+// Uint64N returns a random uint64 in [0, n) from the default source
+// Panics if n == 0
+func Uint64N(n uint64) uint64 {
+	r := getGlobal()
+	defer putGlobal(r)
+	return r.Uint64N(n)
+}
+
+// Uint32N returns a random uint32 in [0, n) from the default source
+// Panics if n == 0
+func Uint32N(n uint32) uint32 {
+	r := getGlobal()
+	defer putGlobal(r)
+	return r.Uint32N(n)
+}
+
+// Float64 returns a random float64 in [0.0, 1.0) from the default source
+func Float64() float64 {
+	r := getGlobal()
+	v := r.Float64()
+	putGlobal(r)
+	return v
+}
+
+// Float32 returns a random float32 in [0.0, 1.0) from the default source
+func Float32() float32 {
+	r := getGlobal()
+	v := r.Float32()
+	putGlobal(r)
+	return v
+}
+
+// NormFloat64 returns a standard normal float64 from the default source
+func NormFloat64() float64 {
+	r := getGlobal()
+	v := r.NormFloat64()
+	putGlobal(r)
+	return v
+}
+
+// ExpFloat64 returns an exponential float64 with rate 1 from the default
+// source
+func ExpFloat64() float64 {
+	r := getGlobal()
+	v := r.ExpFloat64()
+	putGlobal(r)
+	return v
+}
+
+// Perm returns a random permutation of [0, n) from the default source
+// Panics if n < 0
+func Perm(n int) []int {
+	r := getGlobal()
+	defer putGlobal(r)
+	return r.Perm(n)
+}
+
+// Shuffle pseudo-randomizes the order of n elements using the default
+// source
+// Panics if n < 0
+func Shuffle(n int, swap func(i, j int)) {
+	r := getGlobal()
+	defer putGlobal(r)
+	r.Shuffle(n, swap)
+}
+
+// Read fills buf with random bytes from the default source
+// It always returns len(buf), nil.
+func Read(buf []byte) (n int, err error) {
+	r := getGlobal()
+	defer putGlobal(r)
+	return r.Read(buf)
+}
@@ -0,0 +1,70 @@
+package rand
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestGlobalRanges(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		if v := IntN(10); v < 0 || v >= 10 {
+			t.Fatalf("IntN(10) = %d", v)
+		}
+		if v := Intn(10); v < 0 || v >= 10 {
+			t.Fatalf("Intn(10) = %d", v)
+		}
+		if v := Uint64N(3); v >= 3 {
+			t.Fatalf("Uint64N(3) = %d", v)
+		}
+		if v := Int(); v < 0 {
+			t.Fatalf("Int() = %d", v)
+		}
+		if v := Float64(); v < 0 || v >= 1 {
+			t.Fatalf("Float64() = %v", v)
+		}
+	}
+	p := Perm(50)
+	seen := make([]bool, 50)
+	for _, v := range p {
+		if seen[v] {
+			t.Fatalf("Perm(50) repeats %d", v)
+		}
+		seen[v] = true
+	}
+}
+
+func TestGlobalIsSeeded(t *testing.T) {
+	// Independently created default generators must not share a stream
+	a, b := newGlobal(), newGlobal()
+	if a.Uint64() == b.Uint64() && a.Uint64() == b.Uint64() {
+		t.Fatal("default generators are not randomly seeded")
+	}
+
+	x, y := make([]byte, 32), make([]byte, 32)
+	Read(x)
+	Read(y)
+	if bytes.Equal(x, y) {
+		t.Fatal("consecutive Read calls returned the same bytes")
+	}
+}
+
+func TestGlobalConcurrent(t *testing.T) {
+	// Run with -race: the default source must be safe for concurrent use
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+		wg.Go(func() {
+			buf := make([]byte, 13)
+			s := []int{1, 2, 3, 4}
+			for i := 0; i < 2000; i++ {
+				Uint64()
+				IntN(100)
+				Float64()
+				NormFloat64()
+				Read(buf)
+				Shuffle(len(s), func(i, j int) { s[i], s[j] = s[j], s[i] })
+			}
+		})
+	}
+	wg.Wait()
+}
@@ -0,0 +1,44 @@
+package rand_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/vrypan/ring30mix/internal/vectors"
+	"github.com/vrypan/ring30mix/rand"
+)
+
+// TestGoldenVectors checks the stream contract: every version must keep
+// reproducing testdata/vectors.jsonl. Run it under GOARCH=386 too (make
+// golden) to cover 32-bit Int.
+func TestGoldenVectors(t *testing.T) {
+	f, err := os.Open("testdata/vectors.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	vs, err := vectors.Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vectors.Complete(vs); err != nil {
+		t.Fatal(err)
+	}
+	for _, vec := range vs {
+		if err := vectors.Check(vec); err != nil {
+			t.Error(err)
+		}
+	}
+
+	// A default vector for an older version, as left behind by moving
+	// LatestVersion, must fail
+	for _, vec := range vs {
+		if vec.Default {
+			vec.Version = rand.V1
+			if vectors.Check(vec) == nil {
+				t.Error("default vector pinned to an old version passed")
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package rand
+
+import "iter"
+
+// This file contains iterator versions of the generator methods, for use
+// with range-over-func:
+//
+//	for roll := range rng.IntsN(6) {
+//		if roll == 5 {
+//			break
+//		}
+//	}
+//
+// The sequences are endless unless noted and draw from r as they are
+// consumed, so ranging over the same sequence twice continues the stream
+// rather than repeating it. Like r itself, they are not safe for
+// concurrent use.
+
+// Uint64s returns an endless sequence of Uint64 values
+func (r *RNG) Uint64s() iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		for yield(r.Uint64()) {
+		}
+	}
+}
+
+// IntsN returns an endless sequence of IntN(n) values
+// Panics if n <= 0
+func (r *RNG) IntsN(n int) iter.Seq[int] {
+	if n <= 0 {
+		panic("invalid argument to IntsN")
+	}
+	return func(yield func(int) bool) {
+		for yield(r.IntN(n)) {
+		}
+	}
+}
+
+// Floats returns an endless sequence of Float64 values
+func (r *RNG) Floats() iter.Seq[float64] {
+	return func(yield func(float64) bool) {
+		for yield(r.Float64()) {
+		}
+	}
+}
+
+// NormFloats returns an endless sequence of NormFloat64 values
+func (r *RNG) NormFloats() iter.Seq[float64] {
+	return func(yield func(float64) bool) {
+		for yield(r.NormFloat64()) {
+		}
+	}
+}
+
+// ExpFloats returns an endless sequence of ExpFloat64 values
+func (r *RNG) ExpFloats() iter.Seq[float64] {
+	return func(yield func(float64) bool) {
+		for yield(r.ExpFloat64()) {
+		}
+	}
+}
+
+// PermSeq returns a random permutation of [0, n) as a lazy sequence
+// It runs Fisher-Yates forwards and remembers only the displaced
+// positions, so taking the first k values costs O(k) time and memory
+// whatever n is. The order differs from Perm(n) with the same state.
+// Panics if n < 0
+func (r *RNG) PermSeq(n int) iter.Seq[int] {
+	if n < 0 {
+		panic("invalid argument to PermSeq")
+	}
+	return func(yield func(int) bool) {
+		// moved[i] holds the value at position i if it is not i
+		moved := make(map[int]int)
+		at := func(i int) int {
+			if v, ok := moved[i]; ok {
+				return v
+			}
+			return i
+		}
+		for i := 0; i < n; i++ {
+			j := i + int(r.uint64n(uint64(n-i)))
+			v := at(j)
+			if j != i {
+				moved[j] = at(i)
+			}
+			delete(moved, i)
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
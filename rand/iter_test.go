@@ -0,0 +1,100 @@
+package rand
+
+import (
+	"iter"
+	"math"
+	"slices"
+	"testing"
+)
+
+// take collects the first n values of seq
+func take[T any](seq iter.Seq[T], n int) []T {
+	var out []T
+	if n == 0 {
+		return out
+	}
+	for v := range seq {
+		if out = append(out, v); len(out) == n {
+			break
+		}
+	}
+	return out
+}
+
+func TestIteratorsMatchMethods(t *testing.T) {
+	const n = 50
+	check := func(name string, got, want []float64) {
+		t.Helper()
+		if !slices.Equal(got, want) {
+			t.Errorf("%s does not match the scalar method", name)
+		}
+	}
+	scalar := func(f func(*RNG) float64) []float64 {
+		r := New(3)
+		out := make([]float64, n)
+		for i := range out {
+			out[i] = f(r)
+		}
+		return out
+	}
+	check("Floats", take(New(3).Floats(), n), scalar((*RNG).Float64))
+	check("NormFloats", take(New(3).NormFloats(), n), scalar((*RNG).NormFloat64))
+	check("ExpFloats", take(New(3).ExpFloats(), n), scalar((*RNG).ExpFloat64))
+
+	r, ref := New(4), New(4)
+	for _, v := range take(r.Uint64s(), n) {
+		if v != ref.Uint64() {
+			t.Fatal("Uint64s does not match Uint64")
+		}
+	}
+	// Breaking out of a range must not draw extra values
+	if r.Uint64() != ref.Uint64() {
+		t.Fatal("Uint64s drew past the last value taken")
+	}
+
+	for _, v := range take(New(5).IntsN(6), 1000) {
+		if v < 0 || v >= 6 {
+			t.Fatalf("IntsN(6) yielded %d", v)
+		}
+	}
+}
+
+func TestPermSeq(t *testing.T) {
+	r := New(21)
+	got := slices.Collect(r.PermSeq(1000))
+	slices.Sort(got)
+	for i, v := range got {
+		if v != i {
+			t.Fatalf("PermSeq(1000) is not a permutation: position %d holds %d", i, v)
+		}
+	}
+
+	// All 24 orders of 4 elements are equally likely; 23 degrees of
+	// freedom, p = 0.001
+	const trials = 48_000
+	index := map[[4]int]int{}
+	counts := make([]int, 24)
+	for i := 0; i < trials; i++ {
+		var p [4]int
+		copy(p[:], slices.Collect(r.PermSeq(4)))
+		if _, ok := index[p]; !ok {
+			index[p] = len(index)
+		}
+		counts[index[p]]++
+	}
+	if len(index) != 24 {
+		t.Fatalf("saw %d distinct orders, want 24", len(index))
+	}
+	if x2 := chiSquare(counts, trials); x2 > 49.7 {
+		t.Errorf("PermSeq(4) chi-square = %.1f", x2)
+	}
+
+	// Taking a prefix of a huge permutation is cheap
+	prefix := take(r.PermSeq(math.MaxInt), 5)
+	if len(prefix) != 5 {
+		t.Fatalf("got %d values", len(prefix))
+	}
+	if len(slices.Collect(r.PermSeq(0))) != 0 {
+		t.Fatal("PermSeq(0) is not empty")
+	}
+}
@@ -0,0 +1,88 @@
+//go:build amd64 && !purego
+
+package rand
+
+import "unsafe"
+
+// This file selects the amd64 assembly kernels in kernel_amd64.s. They
+// compute exactly what the Go loops compute, and are used when the CPU
+// and OS support AVX2 or AVX-512. Build with -tags purego to use the Go
+// code everywhere.
+
+// Kernels in use, set from CPUID at startup
+var (
+	useAVX2   bool // AVX2
+	useAVX512 bool // AVX-512 F, DQ and VL
+)
+
+func init() {
+	maxLeaf, _, _, _ := cpuid(0, 0)
+	if maxLeaf < 7 {
+		return
+	}
+	_, _, ecx1, _ := cpuid(1, 0)
+	const osxsave, avx = 1 << 27, 1 << 28
+	if ecx1&(osxsave|avx) != osxsave|avx {
+		return
+	}
+	// The OS must save the YMM (and for AVX-512 the opmask and ZMM) state
+	xcr0, _ := xgetbv()
+	_, ebx7, _, _ := cpuid(7, 0)
+	const avx2, avx512f, avx512dq, avx512vl = 1 << 5, 1 << 16, 1 << 17, 1 << 31
+	useAVX2 = xcr0&0x6 == 0x6 && ebx7&avx2 != 0
+	useAVX512 = useAVX2 && xcr0&0xe6 == 0xe6 &&
+		ebx7&(avx512f|avx512dq|avx512vl) == avx512f|avx512dq|avx512vl
+}
+
+// stepMixBlocks does blocks times what FillUint64 does per step: step s,
+// then store the four mixed words to dst
+// It returns false, doing nothing, if no kernel runs on this CPU.
+func stepMixBlocks(s *[4]uint64, dst unsafe.Pointer, blocks int) bool {
+	switch {
+	case useAVX512:
+		stepMixBlocksAVX512(s, dst, blocks)
+	case useAVX2:
+		stepMixBlocksAVX2(s, dst, blocks)
+	default:
+		return false
+	}
+	return true
+}
+
+// multiRoundsAsm is multiRounds for rounds whole rounds written to dst
+// It returns false, doing nothing, if no kernel runs on this CPU.
+func multiRoundsAsm(s *[4][8]uint64, lanes int, dst unsafe.Pointer, rounds int) bool {
+	switch {
+	case lanes == 8 && useAVX512:
+		multiRoundsAVX512(&s[0][0], dst, rounds)
+	case useAVX2:
+		// One pass per group of four lanes
+		for l := 0; l < lanes; l += 4 {
+			multiRoundsAVX2(&s[0][l], unsafe.Add(dst, 8*l), rounds, lanes)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+//go:noescape
+func stepMixBlocksAVX2(s *[4]uint64, dst unsafe.Pointer, blocks int)
+
+//go:noescape
+func stepMixBlocksAVX512(s *[4]uint64, dst unsafe.Pointer, blocks int)
+
+// multiRoundsAVX2 runs lanes l..l+3 of a MultiRNG with lanes lanes,
+// where s points at the state of lane l and dst at its first word
+//
+//go:noescape
+func multiRoundsAVX2(s *uint64, dst unsafe.Pointer, rounds, lanes int)
+
+// multiRoundsAVX512 runs all lanes of an 8-lane MultiRNG
+//
+//go:noescape
+func multiRoundsAVX512(s *uint64, dst unsafe.Pointer, rounds int)
+
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+func xgetbv() (eax, edx uint32)
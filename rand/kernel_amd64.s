@@ -0,0 +1,231 @@
+//go:build amd64 && !purego
+
+#include "textflag.h"
+
+// mix() multiplier
+DATA golden<>+0(SB)/8, $0x9e3779b97f4a7c15
+GLOBL golden<>(SB), RODATA|NOPTR, $8
+
+// MIX_AVX2 sets out to mix(x) for each 64-bit lane, with Y15 holding the
+// multiplier and Y14 its high half. AVX2 has no 64-bit multiply, so x*c is
+// lo(x)*lo(c) + (hi(x)*lo(c) + lo(x)*hi(c))<<32.
+#define MIX_AVX2(x, out, t, u) \
+	VPSLLQ   $13, x, out;  \
+	VPSRLQ   $51, x, t;    \
+	VPOR     t, out, out;  \
+	VPXOR    x, out, out;  \
+	VPMULUDQ Y15, out, t;  \
+	VPSRLQ   $32, out, u;  \
+	VPMULUDQ Y15, u, u;    \
+	VPMULUDQ Y14, out, out; \
+	VPADDQ   u, out, out;  \
+	VPSLLQ   $32, out, out; \
+	VPADDQ   t, out, out;  \
+	VPSRLQ   $27, out, t;  \
+	VPXOR    t, out, out
+
+// MIX_AVX512 is MIX_AVX2 with a native rotate and multiply
+#define MIX_AVX512(x, out, t) \
+	VPROLQ  $13, x, out;  \
+	VPXORQ  x, out, out;  \
+	VPMULLQ Z15, out, out; \
+	VPSRLQ  $27, out, t;  \
+	VPXORQ  t, out, out
+
+// STEP_AVX2 sets out to the next value of ring word s, whose left and
+// right neighbours are l and r: ((s>>1)|(l<<63)) ^ (s|(s<<1)|(r>>63))
+#define STEP_AVX2(s, l, r, out, t, u) \
+	VPSRLQ $1, s, out;  \
+	VPSLLQ $63, l, t;   \
+	VPOR   t, out, out; \
+	VPSLLQ $1, s, t;    \
+	VPOR   s, t, t;     \
+	VPSRLQ $63, r, u;   \
+	VPOR   u, t, t;     \
+	VPXOR  t, out, out
+
+// STEP_AVX512 is STEP_AVX2 on ZMM registers
+#define STEP_AVX512(s, l, r, out, t, u) \
+	VPSRLQ $1, s, out;  \
+	VPSLLQ $63, l, t;   \
+	VPORQ  t, out, out; \
+	VPSLLQ $1, s, t;    \
+	VPORQ  s, t, t;     \
+	VPSRLQ $63, r, u;   \
+	VPORQ  u, t, t;     \
+	VPXORQ t, out, out
+
+// STEP_RING steps the ring held in Y0, one word per lane. The neighbours
+// are the ring rotated by one word either way.
+#define STEP_RING \
+	VPERMQ $0x93, Y0, Y1; \
+	VPERMQ $0x39, Y0, Y2; \
+	STEP_AVX2(Y0, Y1, Y2, Y3, Y4, Y5); \
+	VMOVDQA Y3, Y0
+
+// func stepMixBlocksAVX2(s *[4]uint64, dst unsafe.Pointer, blocks int)
+TEXT ·stepMixBlocksAVX2(SB), NOSPLIT, $0-24
+	MOVQ s+0(FP), AX
+	MOVQ dst+8(FP), DI
+	MOVQ blocks+16(FP), CX
+	TESTQ CX, CX
+	JLE  ring2done
+	VMOVDQU (AX), Y0
+	VPBROADCASTQ golden<>(SB), Y15
+	VPSRLQ $32, Y15, Y14
+
+ring2loop:
+	STEP_RING
+	MIX_AVX2(Y0, Y6, Y7, Y8)
+	VMOVDQU Y6, (DI)
+	ADDQ $32, DI
+	DECQ CX
+	JNZ  ring2loop
+
+	VMOVDQU Y0, (AX)
+	VZEROUPPER
+
+ring2done:
+	RET
+
+// func stepMixBlocksAVX512(s *[4]uint64, dst unsafe.Pointer, blocks int)
+TEXT ·stepMixBlocksAVX512(SB), NOSPLIT, $0-24
+	MOVQ s+0(FP), AX
+	MOVQ dst+8(FP), DI
+	MOVQ blocks+16(FP), CX
+	TESTQ CX, CX
+	JLE  ring5done
+	VMOVDQU (AX), Y0
+	VPBROADCASTQ golden<>(SB), Y15
+
+ring5loop:
+	STEP_RING
+	VPROLQ  $13, Y0, Y6
+	VPXOR   Y0, Y6, Y6
+	VPMULLQ Y15, Y6, Y6
+	VPSRLQ  $27, Y6, Y7
+	VPXOR   Y7, Y6, Y6
+	VMOVDQU Y6, (DI)
+	ADDQ $32, DI
+	DECQ CX
+	JNZ  ring5loop
+
+	VMOVDQU Y0, (AX)
+	VZEROUPPER
+
+ring5done:
+	RET
+
+// func multiRoundsAVX2(s *uint64, dst unsafe.Pointer, rounds, lanes int)
+// State rows are 64 bytes apart, as in [4][8]uint64.
+TEXT ·multiRoundsAVX2(SB), NOSPLIT, $0-32
+	MOVQ s+0(FP), AX
+	MOVQ dst+8(FP), DI
+	MOVQ rounds+16(FP), CX
+	MOVQ lanes+24(FP), DX
+	TESTQ CX, CX
+	JLE  multi2done
+	SHLQ $3, DX            // bytes between words of a round
+	LEAQ (DX)(DX*2), R8
+	VMOVDQU 0(AX), Y0
+	VMOVDQU 64(AX), Y1
+	VMOVDQU 128(AX), Y2
+	VMOVDQU 192(AX), Y3
+	VPBROADCASTQ golden<>(SB), Y15
+	VPSRLQ $32, Y15, Y14
+
+multi2loop:
+	MIX_AVX2(Y0, Y8, Y9, Y10)
+	VMOVDQU Y8, (DI)
+	MIX_AVX2(Y1, Y8, Y9, Y10)
+	VMOVDQU Y8, (DI)(DX*1)
+	MIX_AVX2(Y2, Y8, Y9, Y10)
+	VMOVDQU Y8, (DI)(DX*2)
+	MIX_AVX2(Y3, Y8, Y9, Y10)
+	VMOVDQU Y8, (DI)(R8*1)
+
+	STEP_AVX2(Y0, Y3, Y1, Y4, Y8, Y9)
+	STEP_AVX2(Y1, Y0, Y2, Y5, Y8, Y9)
+	STEP_AVX2(Y2, Y1, Y3, Y6, Y8, Y9)
+	STEP_AVX2(Y3, Y2, Y0, Y7, Y8, Y9)
+	VMOVDQA Y4, Y0
+	VMOVDQA Y5, Y1
+	VMOVDQA Y6, Y2
+	VMOVDQA Y7, Y3
+
+	LEAQ (DI)(DX*4), DI
+	DECQ CX
+	JNZ  multi2loop
+
+	VMOVDQU Y0, 0(AX)
+	VMOVDQU Y1, 64(AX)
+	VMOVDQU Y2, 128(AX)
+	VMOVDQU Y3, 192(AX)
+	VZEROUPPER
+
+multi2done:
+	RET
+
+// func multiRoundsAVX512(s *uint64, dst unsafe.Pointer, rounds int)
+TEXT ·multiRoundsAVX512(SB), NOSPLIT, $0-24
+	MOVQ s+0(FP), AX
+	MOVQ dst+8(FP), DI
+	MOVQ rounds+16(FP), CX
+	TESTQ CX, CX
+	JLE  multi5done
+	VMOVDQU64 0(AX), Z0
+	VMOVDQU64 64(AX), Z1
+	VMOVDQU64 128(AX), Z2
+	VMOVDQU64 192(AX), Z3
+	VPBROADCASTQ golden<>(SB), Z15
+
+multi5loop:
+	MIX_AVX512(Z0, Z8, Z9)
+	VMOVDQU64 Z8, 0(DI)
+	MIX_AVX512(Z1, Z8, Z9)
+	VMOVDQU64 Z8, 64(DI)
+	MIX_AVX512(Z2, Z8, Z9)
+	VMOVDQU64 Z8, 128(DI)
+	MIX_AVX512(Z3, Z8, Z9)
+	VMOVDQU64 Z8, 192(DI)
+
+	STEP_AVX512(Z0, Z3, Z1, Z4, Z8, Z9)
+	STEP_AVX512(Z1, Z0, Z2, Z5, Z8, Z9)
+	STEP_AVX512(Z2, Z1, Z3, Z6, Z8, Z9)
+	STEP_AVX512(Z3, Z2, Z0, Z7, Z8, Z9)
+	VMOVDQA64 Z4, Z0
+	VMOVDQA64 Z5, Z1
+	VMOVDQA64 Z6, Z2
+	VMOVDQA64 Z7, Z3
+
+	ADDQ $256, DI
+	DECQ CX
+	JNZ  multi5loop
+
+	VMOVDQU64 Z0, 0(AX)
+	VMOVDQU64 Z1, 64(AX)
+	VMOVDQU64 Z2, 128(AX)
+	VMOVDQU64 Z3, 192(AX)
+	VZEROUPPER
+
+multi5done:
+	RET
+
+// func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+TEXT ·cpuid(SB), NOSPLIT, $0-24
+	MOVL eaxArg+0(FP), AX
+	MOVL ecxArg+4(FP), CX
+	CPUID
+	MOVL AX, eax+8(FP)
+	MOVL BX, ebx+12(FP)
+	MOVL CX, ecx+16(FP)
+	MOVL DX, edx+20(FP)
+	RET
+
+// func xgetbv() (eax, edx uint32)
+TEXT ·xgetbv(SB), NOSPLIT, $0-8
+	MOVL $0, CX
+	XGETBV
+	MOVL AX, eax+0(FP)
+	MOVL DX, edx+4(FP)
+	RET
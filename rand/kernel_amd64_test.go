@@ -0,0 +1,140 @@
+//go:build amd64 && !purego
+
+package rand
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// withKernels runs f once per kernel this CPU supports, and once with the
+// Go loops
+func withKernels(t *testing.T, f func(t *testing.T)) {
+	saved2, saved512 := useAVX2, useAVX512
+	defer func() { useAVX2, useAVX512 = saved2, saved512 }()
+	cases := []struct {
+		name         string
+		avx2, avx512 bool
+		supported    bool
+	}{
+		{"go", false, false, true},
+		{"avx2", true, false, saved2},
+		{"avx512", true, true, saved512},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if !c.supported {
+				t.Skip("not supported by this CPU")
+			}
+			useAVX2, useAVX512 = c.avx2, c.avx512
+			f(t)
+		})
+	}
+}
+
+func TestKernelFillUint64(t *testing.T) {
+	withKernels(t, func(t *testing.T) {
+		for seed := uint64(0); seed < 32; seed++ {
+			for skip := 0; skip < 4; skip++ {
+				for _, n := range []int{0, 3, 4, 8, 33, 1024, 4097} {
+					a, b := New(seed), New(seed)
+					a.Advance(uint64(skip))
+					b.Advance(uint64(skip))
+					got := make([]uint64, n)
+					a.FillUint64(got)
+					for i := range got {
+						if want := b.Uint64(); got[i] != want {
+							t.Fatalf("seed %d, skip %d, n %d: word %d = %#x, want %#x", seed, skip, n, i, got[i], want)
+						}
+					}
+					if a.state != b.state || a.pos != b.pos {
+						t.Fatalf("seed %d, skip %d, n %d: state differs after the fill", seed, skip, n)
+					}
+				}
+			}
+		}
+	})
+}
+
+func TestKernelRead(t *testing.T) {
+	const total = 10000
+	withKernels(t, func(t *testing.T) {
+		for seed := uint64(0); seed < 32; seed++ {
+			ref := New(seed)
+			want := make([]byte, 0, total+8)
+			for len(want) < total {
+				want = binary.LittleEndian.AppendUint64(want, ref.Uint64())
+			}
+			want = want[:total]
+			for _, chunks := range [][]int{{total}, {1, 31}, {5, 64}, {8}, {33}, {4096, 3}} {
+				if got := readChunked(New(seed), total, chunks); !bytes.Equal(got, want) {
+					t.Fatalf("seed %d, chunks %v: byte stream differs", seed, chunks)
+				}
+			}
+		}
+	})
+}
+
+func TestKernelMulti(t *testing.T) {
+	withKernels(t, func(t *testing.T) {
+		for _, lanes := range []int{4, 8} {
+			for seed := uint64(0); seed < 8; seed++ {
+				words := multiReference(seed, lanes, 2000)
+				got := make([]uint64, 1500)
+				m := NewMulti(seed, lanes)
+				m.FillUint64(got[:7])
+				m.FillUint64(got[7:])
+				for i := range got {
+					if got[i] != words[i] {
+						t.Fatalf("lanes %d, seed %d: word %d = %#x, want %#x", lanes, seed, i, got[i], words[i])
+					}
+				}
+
+				want := make([]byte, 0, 8*len(words))
+				for _, w := range words {
+					want = binary.LittleEndian.AppendUint64(want, w)
+				}
+				if b := readChunked(NewMulti(seed, lanes), len(want), []int{3, 1021}); !bytes.Equal(b, want) {
+					t.Fatalf("lanes %d, seed %d: byte stream differs", lanes, seed)
+				}
+			}
+		}
+	})
+}
+
+func BenchmarkKernel_Read32KB(b *testing.B) {
+	saved2, saved512 := useAVX2, useAVX512
+	defer func() { useAVX2, useAVX512 = saved2, saved512 }()
+	cases := []struct {
+		name         string
+		avx2, avx512 bool
+		supported    bool
+	}{
+		{"go", false, false, true},
+		{"avx2", true, false, saved2},
+		{"avx512", true, true, saved512},
+	}
+	buf := make([]byte, 32<<10)
+	for _, c := range cases {
+		for _, gen := range []struct {
+			name string
+			r    interface{ Read([]byte) (int, error) }
+		}{
+			{"RNG", New(12345)},
+			{"Multi4", NewMulti(12345, 4)},
+			{"Multi8", NewMulti(12345, 8)},
+		} {
+			b.Run(c.name+"/"+gen.name, func(b *testing.B) {
+				if !c.supported {
+					b.Skip("not supported by this CPU")
+				}
+				useAVX2, useAVX512 = c.avx2, c.avx512
+				b.SetBytes(int64(len(buf)))
+				for i := 0; i < b.N; i++ {
+					gen.r.Read(buf)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !amd64 || purego
+
+package rand
+
+import "unsafe"
+
+// Without assembly kernels the callers fall back to their Go loops
+
+func stepMixBlocks(s *[4]uint64, dst unsafe.Pointer, blocks int) bool {
+	return false
+}
+
+func multiRoundsAsm(s *[4][8]uint64, lanes int, dst unsafe.Pointer, rounds int) bool {
+	return false
+}
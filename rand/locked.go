@@ -0,0 +1,450 @@
+package rand
+
+import (
+	"encoding"
+	"encoding/json"
+	"io"
+	mathrand "math/rand"
+	"sync"
+)
+
+// This file contains the concurrency helpers for servers: Locked shares
+// one deterministic stream between goroutines, and Pool hands each
+// goroutine its own independent generator.
+
+// Locked is an RNG guarded by a mutex, safe for concurrent use
+// All goroutines draw from one deterministic stream, so the values are
+// reproducible from the seed but their assignment to goroutines depends
+// on scheduling. For throughput rather than a shared stream, use Pool.
+// Create Locked values with NewLocked or NewLockedFrom. The zero value is
+// only usable as a target for unmarshaling, as encoding/json allocates it.
+type Locked struct {
+	mu  sync.Mutex
+	rng *RNG
+}
+
+var (
+	_ mathrand.Source64          = (*Locked)(nil)
+	_ encoding.BinaryMarshaler   = (*Locked)(nil)
+	_ encoding.BinaryUnmarshaler = (*Locked)(nil)
+	_ encoding.TextMarshaler     = (*Locked)(nil)
+	_ encoding.TextUnmarshaler   = (*Locked)(nil)
+	_ json.Marshaler             = (*Locked)(nil)
+	_ json.Unmarshaler           = (*Locked)(nil)
+)
+
+// NewLocked creates a new concurrency-safe RNG from a seed
+func NewLocked(seed uint64) *Locked {
+	return &Locked{rng: New(seed)}
+}
+
+// NewLockedFrom wraps an existing generator
+// The caller must not use r directly afterwards.
+func NewLockedFrom(r *RNG) *Locked {
+	return &Locked{rng: r}
+}
+
+// Seed implements math/rand.Source
+func (l *Locked) Seed(seed int64) {
+	l.mu.Lock()
+	l.rng.Seed(seed)
+	l.mu.Unlock()
+}
+
+// Reseed resets the stream to the state New(seed) would produce
+func (l *Locked) Reseed(seed uint64) {
+	l.mu.Lock()
+	l.rng.Reseed(seed)
+	l.mu.Unlock()
+}
+
+// Uint64 returns a random uint64
+func (l *Locked) Uint64() uint64 {
+	l.mu.Lock()
+	v := l.rng.Uint64()
+	l.mu.Unlock()
+	return v
+}
+
+// Uint32 returns a random uint32
+func (l *Locked) Uint32() uint32 {
+	l.mu.Lock()
+	v := l.rng.Uint32()
+	l.mu.Unlock()
+	return v
+}
+
+// Uint returns a random uint
+func (l *Locked) Uint() uint {
+	l.mu.Lock()
+	v := l.rng.Uint()
+	l.mu.Unlock()
+	return v
+}
+
+// Int returns a non-negative random int
+func (l *Locked) Int() int {
+	l.mu.Lock()
+	v := l.rng.Int()
+	l.mu.Unlock()
+	return v
+}
+
+// Int63 returns a non-negative random int64
+func (l *Locked) Int63() int64 {
+	l.mu.Lock()
+	v := l.rng.Int63()
+	l.mu.Unlock()
+	return v
+}
+
+// Int31 returns a non-negative random int32
+func (l *Locked) Int31() int32 {
+	l.mu.Lock()
+	v := l.rng.Int31()
+	l.mu.Unlock()
+	return v
+}
+
+// Int64 returns a non-negative random int64
+func (l *Locked) Int64() int64 {
+	l.mu.Lock()
+	v := l.rng.Int64()
+	l.mu.Unlock()
+	return v
+}
+
+// Int32 returns a non-negative random int32
+func (l *Locked) Int32() int32 {
+	l.mu.Lock()
+	v := l.rng.Int32()
+	l.mu.Unlock()
+	return v
+}
+
+// IntN returns a random int in [0, n)
+// Panics if n <= 0
+func (l *Locked) IntN(n int) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rng.IntN(n)
+}
+
+// Intn returns a random int in [0, n)
+// Panics if n <= 0
+func (l *Locked) Intn(n int) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rng.Intn(n)
+}
+
+// Int64N returns a random int64 in [0, n)
+// Panics if n <= 0
+func (l *Locked) Int64N(n int64) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rng.Int64N(n)
+}
+
+// Int63n returns a random int64 in [0, n)
+// Panics if n <= 0
+func (l *Locked) Int63n(n int64) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rng.Int63n(n)
+}
+
+// Int32N returns a random int32 in [0, n)
+// Panics if n <= 0
+func (l *Locked) Int32N(n int32) int32 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rng.Int32N(n)
+}
+
+// Int31n returns a random int32 in [0, n)
+// Panics if n <= 0
+func (l *Locked) Int31n(n int32) int32 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rng.Int31n(n)
+}
+
+// UintN returns a random uint in [0, n)
+// Panics if n == 0
+func (l *Locked) UintN(n uint) uint {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rng.UintN(n)
+}
+
+// Uint64N returns a random uint64 in [0, n)
+// Panics if n == 0
+func (l *Locked) Uint64N(n uint64) uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rng.Uint64N(n)
+}
+
+// Uint32N returns a random uint32 in [0, n)
+// Panics if n == 0
+func (l *Locked) Uint32N(n uint32) uint32 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rng.Uint32N(n)
+}
+
+// Float64 returns a random float64 in [0.0, 1.0)
+func (l *Locked) Float64() float64 {
+	l.mu.Lock()
+	v := l.rng.Float64()
+	l.mu.Unlock()
+	return v
+}
+
+// Float32 returns a random float32 in [0.0, 1.0)
+func (l *Locked) Float32() float32 {
+	l.mu.Lock()
+	v := l.rng.Float32()
+	l.mu.Unlock()
+	return v
+}
+
+// Float64Open returns a random float64 in (0.0, 1.0)
+func (l *Locked) Float64Open() float64 {
+	l.mu.Lock()
+	v := l.rng.Float64Open()
+	l.mu.Unlock()
+	return v
+}
+
+// NormFloat64 returns a normally distributed float64 with mean 0 and stddev 1
+func (l *Locked) NormFloat64() float64 {
+	l.mu.Lock()
+	v := l.rng.NormFloat64()
+	l.mu.Unlock()
+	return v
+}
+
+// ExpFloat64 returns an exponentially distributed float64 with rate 1
+func (l *Locked) ExpFloat64() float64 {
+	l.mu.Lock()
+	v := l.rng.ExpFloat64()
+	l.mu.Unlock()
+	return v
+}
+
+// FillUint64 fills dst with Uint64 values
+func (l *Locked) FillUint64(dst []uint64) {
+	l.mu.Lock()
+	l.rng.FillUint64(dst)
+	l.mu.Unlock()
+}
+
+// FillFloat64 fills dst with Float64 values
+func (l *Locked) FillFloat64(dst []float64) {
+	l.mu.Lock()
+	l.rng.FillFloat64(dst)
+	l.mu.Unlock()
+}
+
+// FillFloat32 fills dst with Float32 values
+func (l *Locked) FillFloat32(dst []float32) {
+	l.mu.Lock()
+	l.rng.FillFloat32(dst)
+	l.mu.Unlock()
+}
+
+// FillNormal fills dst with NormFloat64 values
+func (l *Locked) FillNormal(dst []float64) {
+	l.mu.Lock()
+	l.rng.FillNormal(dst)
+	l.mu.Unlock()
+}
+
+// FillIntN fills dst with IntN(n) values
+// Panics if n <= 0
+func (l *Locked) FillIntN(dst []int, n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rng.FillIntN(dst, n)
+}
+
+// Perm returns a random permutation of the integers [0, n)
+// Panics if n < 0
+func (l *Locked) Perm(n int) []int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rng.Perm(n)
+}
+
+// Shuffle pseudo-randomizes the order of n elements
+// The lock is held while swap runs, so swap must not use l.
+// Panics if n < 0
+func (l *Locked) Shuffle(n int, swap func(i, j int)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rng.Shuffle(n, swap)
+}
+
+// Read implements io.Reader
+func (l *Locked) Read(buf []byte) (n int, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rng.Read(buf)
+}
+
+// WriteTo implements io.WriterTo
+// The lock is held for the whole (endless) copy.
+func (l *Locked) WriteTo(w io.Writer) (n int64, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rng.WriteTo(w)
+}
+
+// Advance moves the stream forward as if Uint64 had been called n times
+func (l *Locked) Advance(n uint64) {
+	l.mu.Lock()
+	l.rng.Advance(n)
+	l.mu.Unlock()
+}
+
+// SkipBytes moves the stream forward as if Read had returned n bytes
+func (l *Locked) SkipBytes(n uint64) {
+	l.mu.Lock()
+	l.rng.SkipBytes(n)
+	l.mu.Unlock()
+}
+
+// Split returns a new, unlocked generator independent of the stream
+func (l *Locked) Split() *RNG {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rng.Split()
+}
+
+// Derive returns an unlocked child generator identified by labels
+// Panics on unsupported label types, like RNG.Derive.
+func (l *Locked) Derive(path ...any) *RNG {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rng.Derive(path...)
+}
+
+// Clone returns an unlocked snapshot of the stream
+func (l *Locked) Clone() *RNG {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rng.Clone()
+}
+
+// SetVersion selects the algorithms used for derived values
+// Panics if v is not a known version
+func (l *Locked) SetVersion(v Version) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rng.SetVersion(v)
+}
+
+// Version returns the version the generator follows
+func (l *Locked) Version() Version {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rng.Version()
+}
+
+// target returns the generator to unmarshal into, allocating it for a
+// zero Locked. The caller holds l.mu.
+func (l *Locked) target() *RNG {
+	if l.rng == nil {
+		l.rng = &RNG{}
+	}
+	return l.rng
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (l *Locked) MarshalBinary() ([]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rng.MarshalBinary()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (l *Locked) UnmarshalBinary(data []byte) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.target().UnmarshalBinary(data)
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (l *Locked) MarshalText() ([]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rng.MarshalText()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (l *Locked) UnmarshalText(text []byte) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.target().UnmarshalText(text)
+}
+
+// MarshalJSON implements json.Marshaler
+func (l *Locked) MarshalJSON() ([]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rng.MarshalJSON()
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (l *Locked) UnmarshalJSON(data []byte) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.target().UnmarshalJSON(data)
+}
+
+// Pool hands out independent generators to goroutines and takes them back
+// Each generator is split from a parent stream, so the set of streams is
+// derived from the seed, though which goroutine gets which one depends on
+// scheduling. Like sync.Pool, which it is built on, it keeps a cache per
+// P and may drop idle generators at garbage collection. The zero value is
+// ready to use and splits from a randomly seeded parent, like the
+// package-level functions.
+type Pool struct {
+	mu     sync.Mutex
+	parent *RNG
+	pool   sync.Pool
+}
+
+// NewPool creates a pool whose generators are split from New(seed)
+func NewPool(seed uint64) *Pool {
+	return NewPoolFrom(New(seed))
+}
+
+// NewPoolFrom creates a pool whose generators are split from r
+// The caller must not use r directly afterwards.
+func NewPoolFrom(r *RNG) *Pool {
+	return &Pool{parent: r}
+}
+
+// Get returns a generator for the exclusive use of the caller
+func (p *Pool) Get() *RNG {
+	if r, ok := p.pool.Get().(*RNG); ok {
+		return r
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.parent == nil {
+		p.parent = newGlobal()
+	}
+	return p.parent.Split()
+}
+
+// Put returns a generator obtained from Get
+// The caller must not use r afterwards. Put(nil) is a no-op.
+func (p *Pool) Put(r *RNG) {
+	if r != nil {
+		p.pool.Put(r)
+	}
+}
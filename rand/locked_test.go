@@ -0,0 +1,177 @@
+package rand
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"encoding/xml"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestLockedSharesOneStream(t *testing.T) {
+	// Concurrent draws must partition the sequential stream exactly: no
+	// value lost or drawn twice
+	const goroutines, draws = 8, 1000
+	l := NewLocked(2024)
+	got := make([][]uint64, goroutines)
+	var wg sync.WaitGroup
+	for g := range got {
+		wg.Go(func() {
+			for i := 0; i < draws; i++ {
+				got[g] = append(got[g], l.Uint64())
+			}
+		})
+	}
+	wg.Wait()
+
+	all := slices.Concat(got...)
+	want := make([]uint64, 0, len(all))
+	ref := New(2024)
+	for range all {
+		want = append(want, ref.Uint64())
+	}
+	slices.Sort(all)
+	slices.Sort(want)
+	if !slices.Equal(all, want) {
+		t.Fatal("concurrent Locked draws are not a permutation of the sequential stream")
+	}
+}
+
+func TestLockedConcurrentMethods(t *testing.T) {
+	// Run with -race
+	l := NewLocked(1)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Go(func() {
+			buf := make([]byte, 11)
+			for i := 0; i < 500; i++ {
+				l.IntN(10)
+				l.Float64()
+				l.NormFloat64()
+				l.Read(buf)
+				l.Perm(5)
+				l.Split()
+				l.Derive("x", i)
+			}
+		})
+	}
+	wg.Wait()
+
+	data, err := l.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := NewLocked(0)
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if l.Uint64() != restored.Uint64() {
+		t.Fatal("restored Locked diverges")
+	}
+
+	// Text and JSON forms match RNG's
+	text, err := l.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := restored.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+	if l.Uint64() != restored.Uint64() {
+		t.Fatal("Locked restored from text diverges")
+	}
+	js, err := json.Marshal(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := json.Marshal(l.Clone()); string(js) != string(want) {
+		t.Fatalf("Locked JSON %s, want %s", js, want)
+	}
+	if err := json.Unmarshal(js, restored); err != nil {
+		t.Fatal(err)
+	}
+	if l.Uint64() != restored.Uint64() {
+		t.Fatal("Locked restored from JSON diverges")
+	}
+}
+
+func TestLockedField(t *testing.T) {
+	// Decoders allocate a zero Locked for a pointer field: gob restores it
+	// through UnmarshalBinary, xml through UnmarshalText
+	type wrapper struct{ R *Locked }
+	codecs := []struct {
+		name      string
+		marshal   func(any) ([]byte, error)
+		unmarshal func([]byte, any) error
+	}{
+		{"json", json.Marshal, json.Unmarshal},
+		{"text", xml.Marshal, xml.Unmarshal},
+		{"binary", func(v any) ([]byte, error) {
+			var buf bytes.Buffer
+			err := gob.NewEncoder(&buf).Encode(v)
+			return buf.Bytes(), err
+		}, func(data []byte, v any) error {
+			return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+		}},
+	}
+	for _, c := range codecs {
+		in := wrapper{NewLocked(1)}
+		in.R.Uint64()
+		data, err := c.marshal(in)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		var out wrapper
+		if err := c.unmarshal(data, &out); err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if out.R == nil {
+			t.Fatalf("%s: field not restored", c.name)
+		}
+		sameStream(t, in.R.Clone(), out.R.Clone(), 10)
+	}
+}
+
+func TestPool(t *testing.T) {
+	p := NewPool(7)
+	a, b := p.Get(), p.Get()
+	if a == b {
+		t.Fatal("Get returned the same generator twice")
+	}
+	checkIndependent(t, "pooled generators", a.Clone(), b.Clone())
+	p.Put(a)
+	p.Put(b)
+	p.Put(nil)
+
+	// Run with -race: each goroutine has exclusive use of its generator
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+		wg.Go(func() {
+			for i := 0; i < 500; i++ {
+				r := p.Get()
+				r.Uint64()
+				r.Read(make([]byte, 5))
+				p.Put(r)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func TestPoolStreamsFollowSeed(t *testing.T) {
+	// Freshly split generators come from the parent's Split sequence
+	p, parent := NewPool(99), New(99)
+	for i := 0; i < 3; i++ {
+		sameStream(t, p.Get(), parent.Split(), 10)
+	}
+}
+
+func TestPoolZeroValue(t *testing.T) {
+	var p Pool
+	a, b := p.Get(), p.Get()
+	checkIndependent(t, "zero Pool generators", a.Clone(), b.Clone())
+	p.Put(a)
+	p.Put(b)
+}
@@ -0,0 +1,235 @@
+package rand
+
+import (
+	"encoding"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+)
+
+// This file contains state serialization for checkpointing.
+//
+// Binary layout (little-endian):
+//
+//	magic    [4]byte  "R30M"
+//	version  uint8    encoding version (currently 1)
+//	state    [4]uint64
+//	pos      uint8    0-4
+//	ntail    uint8    0-7, buffered Read bytes
+//	tail     uint64   buffered Read bytes
+//	mixer    uint8    StdMixer, MixGolden for RNG
+//	stream   uint8    Version of the derived-value algorithms
+//	checksum uint32   CRC-32 (IEEE) of all preceding bytes
+//
+// The text and JSON forms are the hex encoding of the binary form.
+
+const (
+	marshalMagic   = "R30M"
+	marshalVersion = 1
+	marshalSize    = 4 + 1 + 32 + 1 + 9 + 1 + 1 + 4
+)
+
+// ErrCustomMixer is returned when marshaling a MixedRNG whose mixer is not
+// a StdMixer, since arbitrary code can't be serialized
+var ErrCustomMixer = errors.New("rand: cannot marshal a MixedRNG with a custom Mixer")
+
+// ErrInvalidEncoding is returned when restoring from corrupt or unknown data
+var ErrInvalidEncoding = errors.New("rand: invalid RNG encoding")
+
+var (
+	_ encoding.BinaryMarshaler   = (*RNG)(nil)
+	_ encoding.BinaryUnmarshaler = (*RNG)(nil)
+	_ encoding.TextMarshaler     = (*RNG)(nil)
+	_ encoding.TextUnmarshaler   = (*RNG)(nil)
+	_ json.Marshaler             = (*RNG)(nil)
+	_ json.Unmarshaler           = (*RNG)(nil)
+
+	_ encoding.BinaryMarshaler   = (*MixedRNG)(nil)
+	_ encoding.BinaryUnmarshaler = (*MixedRNG)(nil)
+	_ encoding.TextMarshaler     = (*MixedRNG)(nil)
+	_ encoding.TextUnmarshaler   = (*MixedRNG)(nil)
+	_ json.Marshaler             = (*MixedRNG)(nil)
+	_ json.Unmarshaler           = (*MixedRNG)(nil)
+)
+
+// Clone returns an independent copy of the generator
+// The copy produces exactly the same stream as r from this point on.
+func (r *RNG) Clone() *RNG {
+	c := *r
+	return &c
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (r *RNG) MarshalBinary() ([]byte, error) {
+	return r.appendBinary(MixGolden), nil
+}
+
+// appendBinary encodes the ring, recording mixer as its output function
+func (r *RNG) appendBinary(mixer StdMixer) []byte {
+	buf := make([]byte, 0, marshalSize)
+	buf = append(buf, marshalMagic...)
+	buf = append(buf, marshalVersion)
+	for _, w := range r.state {
+		buf = binary.LittleEndian.AppendUint64(buf, w)
+	}
+	buf = append(buf, byte(r.pos), byte(r.ntail))
+	buf = binary.LittleEndian.AppendUint64(buf, r.tail)
+	buf = append(buf, byte(mixer), byte(r.Version()))
+	return binary.LittleEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf))
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+// Encodings of a MixedRNG with another mixer than MixGolden are rejected.
+// On error the generator is left unchanged.
+func (r *RNG) UnmarshalBinary(data []byte) error {
+	ring, mixer, err := decodeBinary(data)
+	if err != nil {
+		return err
+	}
+	if mixer != MixGolden {
+		return fmt.Errorf("%w: encoded with mixer %v, restore it into a MixedRNG", ErrInvalidEncoding, mixer)
+	}
+	*r = ring
+	return nil
+}
+
+// decodeBinary parses an encoding into a ring and its mixer
+func decodeBinary(data []byte) (RNG, StdMixer, error) {
+	var r RNG
+	if len(data) < 5 || string(data[:4]) != marshalMagic {
+		return r, 0, fmt.Errorf("%w: bad magic", ErrInvalidEncoding)
+	}
+	if version := data[4]; version != marshalVersion {
+		return r, 0, fmt.Errorf("%w: unsupported version %d", ErrInvalidEncoding, version)
+	}
+	if len(data) != marshalSize {
+		return r, 0, fmt.Errorf("%w: length %d, want %d", ErrInvalidEncoding, len(data), marshalSize)
+	}
+	body, sum := data[:marshalSize-4], binary.LittleEndian.Uint32(data[marshalSize-4:])
+	if crc32.ChecksumIEEE(body) != sum {
+		return r, 0, fmt.Errorf("%w: checksum mismatch", ErrInvalidEncoding)
+	}
+
+	for i := range r.state {
+		r.state[i] = binary.LittleEndian.Uint64(body[5+8*i:])
+	}
+	r.pos = int(body[37])
+	if r.pos > 4 {
+		return r, 0, fmt.Errorf("%w: position %d out of range", ErrInvalidEncoding, r.pos)
+	}
+	r.ntail = int(body[38])
+	r.tail = binary.LittleEndian.Uint64(body[39:])
+	if r.ntail > 7 {
+		return r, 0, fmt.Errorf("%w: %d buffered bytes out of range", ErrInvalidEncoding, r.ntail)
+	}
+	mixer := StdMixer(body[47])
+	if mixer >= numStdMixers {
+		return r, 0, fmt.Errorf("%w: unknown mixer %d", ErrInvalidEncoding, body[47])
+	}
+	stream := Version(body[48])
+	if !stream.valid() {
+		return r, 0, fmt.Errorf("%w: unknown stream version %d", ErrInvalidEncoding, body[48])
+	}
+	r.SetVersion(stream)
+	return r, mixer, nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+// Only StdMixer mixers can be encoded; others give ErrCustomMixer.
+func (g *MixedRNG) MarshalBinary() ([]byte, error) {
+	mixer, ok := g.mixer.(StdMixer)
+	if !ok {
+		return nil, ErrCustomMixer
+	}
+	return g.ring.appendBinary(mixer), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+// It restores the mixer too. On error the generator is left unchanged.
+func (g *MixedRNG) UnmarshalBinary(data []byte) error {
+	ring, mixer, err := decodeBinary(data)
+	if err != nil {
+		return err
+	}
+	g.ring, g.mixer = ring, mixer
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler as hex of the binary form
+func (r *RNG) MarshalText() ([]byte, error) {
+	return marshalText(r)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (r *RNG) UnmarshalText(text []byte) error {
+	return unmarshalText(r, text)
+}
+
+// MarshalJSON implements json.Marshaler as a hex string
+func (r *RNG) MarshalJSON() ([]byte, error) {
+	return marshalJSON(r)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (r *RNG) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(r, data)
+}
+
+// MarshalText implements encoding.TextMarshaler as hex of the binary form
+func (g *MixedRNG) MarshalText() ([]byte, error) {
+	return marshalText(g)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (g *MixedRNG) UnmarshalText(text []byte) error {
+	return unmarshalText(g, text)
+}
+
+// MarshalJSON implements json.Marshaler as a hex string
+func (g *MixedRNG) MarshalJSON() ([]byte, error) {
+	return marshalJSON(g)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (g *MixedRNG) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(g, data)
+}
+
+// marshalText returns the hex of m's binary form
+func marshalText(m encoding.BinaryMarshaler) ([]byte, error) {
+	b, err := m.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return hex.AppendEncode(nil, b), nil
+}
+
+// unmarshalText restores u from the hex of its binary form
+func unmarshalText(u encoding.BinaryUnmarshaler, text []byte) error {
+	b, err := hex.AppendDecode(nil, text)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	return u.UnmarshalBinary(b)
+}
+
+// marshalJSON returns m's text form as a JSON string
+func marshalJSON(m encoding.TextMarshaler) ([]byte, error) {
+	text, err := m.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// unmarshalJSON restores u from its text form in a JSON string
+func unmarshalJSON(u encoding.TextUnmarshaler, data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	return u.UnmarshalText([]byte(text))
+}
@@ -0,0 +1,119 @@
+package rand
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// sameStream checks that a and b produce the same next n outputs
+func sameStream(t *testing.T, a, b *RNG, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if x, y := a.Uint64(), b.Uint64(); x != y {
+			t.Fatalf("output %d: %#x != %#x", i, x, y)
+		}
+	}
+}
+
+func TestMarshalBinaryRoundTrip(t *testing.T) {
+	// Cover every position within a step, including pos == 4
+	for skip := 0; skip < 9; skip++ {
+		rng := New(31337)
+		for i := 0; i < skip; i++ {
+			rng.Uint64()
+		}
+		data, err := rng.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var restored RNG
+		if err := restored.UnmarshalBinary(data); err != nil {
+			t.Fatalf("skip %d: %v", skip, err)
+		}
+		sameStream(t, rng, &restored, 100)
+	}
+}
+
+func TestMarshalTextAndJSON(t *testing.T) {
+	rng := New(8)
+	rng.Uint64()
+
+	text, err := rng.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromText RNG
+	if err := fromText.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+
+	type checkpoint struct {
+		Step int
+		RNG  *RNG
+	}
+	js, err := json.Marshal(checkpoint{Step: 3, RNG: rng})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cp checkpoint
+	if err := json.Unmarshal(js, &cp); err != nil {
+		t.Fatal(err)
+	}
+
+	want := rng.Clone()
+	sameStream(t, want.Clone(), &fromText, 50)
+	sameStream(t, want, cp.RNG, 50)
+}
+
+func TestUnmarshalRejectsCorruption(t *testing.T) {
+	rng := New(1)
+	data, _ := rng.MarshalBinary()
+
+	corrupt := func(f func([]byte) []byte) []byte {
+		return f(append([]byte(nil), data...))
+	}
+	cases := map[string][]byte{
+		"empty":     nil,
+		"magic":     corrupt(func(b []byte) []byte { b[0] = 'X'; return b }),
+		"version":   corrupt(func(b []byte) []byte { b[4] = 99; return b }),
+		"truncated": data[:len(data)-1],
+		"state bit": corrupt(func(b []byte) []byte { b[10] ^= 1; return b }),
+		"checksum":  corrupt(func(b []byte) []byte { b[len(b)-1] ^= 0x80; return b }),
+	}
+	for name, in := range cases {
+		var r RNG
+		if err := r.UnmarshalBinary(in); !errors.Is(err, ErrInvalidEncoding) {
+			t.Errorf("%s: got %v, want ErrInvalidEncoding", name, err)
+		}
+	}
+
+	var r RNG
+	if err := r.UnmarshalText([]byte("not hex")); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("bad hex: got %v, want ErrInvalidEncoding", err)
+	}
+}
+
+func TestUnmarshalErrorLeavesStateUnchanged(t *testing.T) {
+	rng := New(5)
+	want := rng.Clone()
+	if err := rng.UnmarshalBinary([]byte("R30M\x01garbage")); err == nil {
+		t.Fatal("expected error")
+	}
+	sameStream(t, rng, want, 10)
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	rng := New(77)
+	rng.Uint64()
+	c := rng.Clone()
+	sameStream(t, rng.Clone(), c.Clone(), 20)
+
+	// Advancing the clone must not affect the original
+	for i := 0; i < 10; i++ {
+		c.Uint64()
+	}
+	fresh := New(77)
+	fresh.Uint64()
+	sameStream(t, rng, fresh, 20)
+}
@@ -0,0 +1,187 @@
+package rand
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// This file contains the pluggable output mixers. RNG always applies
+// mix(); MixedRNG swaps it out, to show how much of the output quality
+// comes from Rule 30 itself and how much from the finalizer.
+
+// Mixer is an output function applied to each ring word
+type Mixer interface {
+	Mix(x uint64) uint64
+}
+
+// MixerFunc adapts an ordinary function to the Mixer interface
+type MixerFunc func(x uint64) uint64
+
+// Mix calls f(x)
+func (f MixerFunc) Mix(x uint64) uint64 {
+	return f(x)
+}
+
+// StdMixer identifies one of the built-in mixers
+// Unlike custom mixers, these survive MarshalBinary.
+type StdMixer uint8
+
+const (
+	MixGolden     StdMixer = iota // rotate-13, golden-ratio multiply, shift-27 (default)
+	MixIdentity                   // raw ring words, for research only
+	MixSplitMix64                 // full SplitMix64 finalizer
+	MixMurmur3                    // MurmurHash3 fmix64
+	MixMoremur                    // Pelle Evensen's moremur
+	numStdMixers
+)
+
+var stdMixerNames = [numStdMixers]string{"golden", "identity", "splitmix64", "murmur3", "moremur"}
+
+// Mix applies the mixer
+func (m StdMixer) Mix(x uint64) uint64 {
+	switch m {
+	case MixGolden:
+		return mix(x)
+	case MixIdentity:
+		return x
+	case MixSplitMix64:
+		return splitmix(x)
+	case MixMurmur3:
+		return murmur3(x)
+	case MixMoremur:
+		return moremur(x)
+	}
+	panic("rand: unknown StdMixer")
+}
+
+// String returns the name accepted by ParseMixer
+func (m StdMixer) String() string {
+	if m < numStdMixers {
+		return stdMixerNames[m]
+	}
+	return fmt.Sprintf("StdMixer(%d)", uint8(m))
+}
+
+// StdMixers returns every built-in mixer
+func StdMixers() []StdMixer {
+	out := make([]StdMixer, numStdMixers)
+	for i := range out {
+		out[i] = StdMixer(i)
+	}
+	return out
+}
+
+// ParseMixer returns the built-in mixer with the given name
+func ParseMixer(name string) (StdMixer, error) {
+	for i, n := range stdMixerNames {
+		if strings.EqualFold(name, n) {
+			return StdMixer(i), nil
+		}
+	}
+	return 0, fmt.Errorf("rand: unknown mixer %q (available: %s)", name, strings.Join(stdMixerNames[:], ", "))
+}
+
+// murmur3 is the MurmurHash3 64-bit finalizer (fmix64)
+func murmur3(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+// moremur is Pelle Evensen's improved Murmur3-style finalizer
+func moremur(x uint64) uint64 {
+	x ^= x >> 27
+	x *= 0x3c79ac492ba7b653
+	x ^= x >> 33
+	x *= 0x1c69b3f74ac4ae35
+	x ^= x >> 27
+	return x
+}
+
+// MixedRNG is a Rule 30 generator with a pluggable output mixer
+// It runs the same ring as RNG and applies m.Mix to each ring word instead
+// of mix(). It is meant for research; keeping the mixer off RNG keeps the
+// default Uint64 free of an extra branch. MixedRNG is a
+// math/rand.Source64, so it can drive math/rand and the test suites.
+type MixedRNG struct {
+	ring  RNG // ring state and Read buffer
+	mixer Mixer
+}
+
+// NewMixed creates a generator from a seed with output mixer m
+// nil selects MixGolden, which gives the same stream as New(seed).
+func NewMixed(seed uint64, m Mixer) *MixedRNG {
+	g := &MixedRNG{}
+	g.ring.Reseed(seed)
+	g.SetMixer(m)
+	return g
+}
+
+// Reseed resets the ring to the state New(seed) would produce
+// The mixer is kept.
+func (g *MixedRNG) Reseed(seed uint64) {
+	g.ring.Reseed(seed)
+}
+
+// Seed implements math/rand.Source
+func (g *MixedRNG) Seed(seed int64) {
+	g.ring.Reseed(uint64(seed))
+}
+
+// SetMixer replaces the output mixer; nil restores MixGolden
+// The ring state is not touched, so the next outputs are the same ring
+// words passed through the new mixer.
+func (g *MixedRNG) SetMixer(m Mixer) {
+	if m == nil {
+		m = MixGolden
+	}
+	g.mixer = m
+}
+
+// Mixer returns the output mixer in use
+func (g *MixedRNG) Mixer() Mixer {
+	return g.mixer
+}
+
+// Uint64 returns the next ring word through the mixer
+func (g *MixedRNG) Uint64() uint64 {
+	r := &g.ring
+	if r.pos == 4 {
+		r.step()
+		r.pos = 0
+	}
+	w := r.state[r.pos]
+	r.pos++
+	return g.mixer.Mix(w)
+}
+
+// Int63 returns a non-negative random int64
+func (g *MixedRNG) Int63() int64 {
+	return int64(g.Uint64() & (1<<63 - 1))
+}
+
+// Read implements io.Reader like RNG.Read
+func (g *MixedRNG) Read(buf []byte) (n int, err error) {
+	readWords(buf, &g.ring.tail, &g.ring.ntail, g.Uint64, nil)
+	return len(buf), nil
+}
+
+// WriteTo implements io.WriterTo like RNG.WriteTo
+func (g *MixedRNG) WriteTo(w io.Writer) (n int64, err error) {
+	buf := make([]byte, 32<<10)
+	for {
+		g.Read(buf)
+		m, err := w.Write(buf)
+		n += int64(m)
+		if err != nil {
+			return n, err
+		}
+		if m != len(buf) {
+			return n, io.ErrShortWrite
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package rand
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestStdMixerValues(t *testing.T) {
+	// SplitMix64 seeded with 0 first outputs the finalizer of the golden
+	// ratio increment
+	if got := MixSplitMix64.Mix(0x9e3779b97f4a7c15); got != 0xe220a8397b1dcdaf {
+		t.Errorf("splitmix64 = %#x, want 0xe220a8397b1dcdaf", got)
+	}
+	for _, m := range StdMixers() {
+		if m == MixIdentity {
+			continue
+		}
+		// Every real mixer spreads a single input bit
+		if out := m.Mix(1); out == 1 || out&^1 == 0 {
+			t.Errorf("%s does not diffuse a single bit: %#x", m, out)
+		}
+	}
+	if MixIdentity.Mix(12345) != 12345 {
+		t.Error("identity mixer changed its input")
+	}
+	if MixGolden.Mix(12345) != mix(12345) {
+		t.Error("golden mixer differs from mix()")
+	}
+}
+
+func TestParseMixer(t *testing.T) {
+	for _, m := range StdMixers() {
+		got, err := ParseMixer(m.String())
+		if err != nil || got != m {
+			t.Errorf("ParseMixer(%q) = %v, %v", m.String(), got, err)
+		}
+	}
+	if _, err := ParseMixer("md5"); err == nil {
+		t.Error("ParseMixer accepted an unknown name")
+	}
+}
+
+func TestMixedRNG(t *testing.T) {
+	// The identity mixer exposes the ring words themselves
+	rng := NewMixed(10, MixIdentity)
+	ref := New(10)
+	for i := 0; i < 8; i++ {
+		if i == 4 {
+			ref.step()
+		}
+		if got, want := rng.Uint64(), ref.state[i%4]; got != want {
+			t.Fatalf("identity output %d: %#x, want %#x", i, got, want)
+		}
+	}
+
+	// The default mixer gives the RNG stream, bytes included
+	a, b := NewMixed(10, nil), New(10)
+	if a.Mixer() != MixGolden {
+		t.Errorf("Mixer() = %v, want golden", a.Mixer())
+	}
+	for i := 0; i < 20; i++ {
+		if x, y := a.Uint64(), b.Uint64(); x != y {
+			t.Fatalf("golden output %d: %#x, want %#x", i, x, y)
+		}
+	}
+	want := readChunked(New(12), 100, []int{100})
+	if got := readChunked(NewMixed(12, MixGolden), 100, []int{3, 5, 13}); !bytes.Equal(got, want) {
+		t.Error("golden Read differs from RNG.Read")
+	}
+
+	// Custom mixers and SetMixer mid-stream
+	neg := MixerFunc(func(x uint64) uint64 { return ^x })
+	c, d := NewMixed(11, nil), NewMixed(11, nil)
+	c.Uint64()
+	d.Uint64()
+	c.SetMixer(neg)
+	d.SetMixer(MixIdentity)
+	for i := 0; i < 10; i++ {
+		if x, y := c.Uint64(), d.Uint64(); x != ^y {
+			t.Fatalf("custom mixer output %d: %#x, want %#x", i, x, ^y)
+		}
+	}
+
+	// Reseeding keeps the mixer
+	d.Seed(11)
+	if d.Mixer() != MixIdentity {
+		t.Errorf("Seed reset the mixer to %v", d.Mixer())
+	}
+}
+
+func TestMarshalMixer(t *testing.T) {
+	rng := NewMixed(3, MixMoremur)
+	rng.Uint64()
+	data, err := rng.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored MixedRNG
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Mixer() != MixMoremur {
+		t.Fatalf("restored mixer %v, want moremur", restored.Mixer())
+	}
+	for i := 0; i < 20; i++ {
+		if x, y := rng.Uint64(), restored.Uint64(); x != y {
+			t.Fatalf("restored output %d: %#x, want %#x", i, y, x)
+		}
+	}
+
+	// An RNG only restores encodings of the default mixer
+	var plain RNG
+	if err := plain.UnmarshalBinary(data); !errors.Is(err, ErrInvalidEncoding) {
+		t.Fatalf("RNG restored a moremur encoding: %v", err)
+	}
+	golden, _ := NewMixed(3, nil).MarshalBinary()
+	if err := plain.UnmarshalBinary(golden); err != nil {
+		t.Fatal(err)
+	}
+
+	custom := NewMixed(3, MixerFunc(func(x uint64) uint64 { return x }))
+	if _, err := custom.MarshalBinary(); !errors.Is(err, ErrCustomMixer) {
+		t.Fatalf("custom mixer marshal: %v, want ErrCustomMixer", err)
+	}
+	if _, err := json.Marshal(custom); !errors.Is(err, ErrCustomMixer) {
+		t.Fatalf("custom mixer JSON: %v, want ErrCustomMixer", err)
+	}
+}
+
+func TestMarshalMixedText(t *testing.T) {
+	// A MixedRNG field keeps its state and mixer through JSON
+	type wrapper struct{ R *MixedRNG }
+	in := wrapper{NewMixed(5, MixMurmur3)}
+	in.R.Uint64()
+	js, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out wrapper
+	if err := json.Unmarshal(js, &out); err != nil {
+		t.Fatal(err)
+	}
+	text, err := in.R.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromText MixedRNG
+	if err := fromText.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+	for _, g := range []*MixedRNG{out.R, &fromText} {
+		if g.Mixer() != MixMurmur3 {
+			t.Fatalf("restored mixer %v, want murmur3", g.Mixer())
+		}
+	}
+	for i := 0; i < 20; i++ {
+		x := in.R.Uint64()
+		if y, z := out.R.Uint64(), fromText.Uint64(); y != x || z != x {
+			t.Fatalf("restored output %d: %#x and %#x, want %#x", i, y, z, x)
+		}
+	}
+}
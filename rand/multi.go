@@ -0,0 +1,159 @@
+package rand
+
+import (
+	"encoding/binary"
+	"unsafe"
+)
+
+// This file contains the multi-lane generator. One ring's step() is a
+// short chain of dependent shifts, so a single RNG leaves most of a wide
+// core idle. MultiRNG runs 4 or 8 independent rings side by side and
+// keeps word w of every lane next to each other, the layout a 4- or
+// 8-wide SIMD register wants. The Go compiler doesn't vectorize loops,
+// so the Go kernels below instead keep one lane's state in registers for
+// a whole block and let the CPU overlap the independent lanes. On amd64
+// the kernels in kernel_amd64.s put 4 or 8 lanes in one vector register.
+//
+// Lanes are the first children of New(seed).Split(), so each lane on its
+// own is exactly an RNG stream. Each round emits the four current words
+// of every lane, then steps every lane once. Within a round the output is
+// word-major: with L lanes, output w*L + l of the round is word w of lane
+// l, which is the (4*round + w)-th Uint64 of that lane's RNG.
+
+// MultiRNG generates from several Rule 30 rings in lockstep
+// It always uses the default mixer, and is not safe for concurrent use.
+type MultiRNG struct {
+	state [4][8]uint64 // state[w][l] is word w of lane l
+	lanes int
+	buf   [32]uint64 // current round, word-major
+	pos   int        // next word of buf, 4*lanes when used up
+	tail  uint64     // unread bytes of the last word used by Read
+	ntail int        // number of bytes left in tail (0-7)
+}
+
+// NewMulti creates a generator of 4 or 8 lanes from a seed
+// Panics if lanes is not 4 or 8
+func NewMulti(seed uint64, lanes int) *MultiRNG {
+	if lanes != 4 && lanes != 8 {
+		panic("invalid argument to NewMulti")
+	}
+	m := &MultiRNG{lanes: lanes, pos: 4 * lanes}
+	parent := New(seed)
+	for l := 0; l < lanes; l++ {
+		m.setLane(l, parent.Split())
+	}
+	return m
+}
+
+// setLane loads lane l from a generator that is at the start of a step
+func (m *MultiRNG) setLane(l int, r *RNG) {
+	for w := range r.state {
+		m.state[w][l] = r.state[w]
+	}
+}
+
+// Lanes returns the number of lanes
+func (m *MultiRNG) Lanes() int {
+	return m.lanes
+}
+
+// Uint64 returns the next word of the interleaved stream
+func (m *MultiRNG) Uint64() uint64 {
+	if m.pos == 4*m.lanes {
+		multiRounds(&m.state, m.lanes, m.buf[:4*m.lanes])
+		m.pos = 0
+	}
+	v := m.buf[m.pos]
+	m.pos++
+	return v
+}
+
+// FillUint64 fills dst with the next words of the interleaved stream
+func (m *MultiRNG) FillUint64(dst []uint64) {
+	i := 0
+	// Finish the current round
+	for ; i < len(dst) && m.pos < 4*m.lanes; i++ {
+		dst[i] = m.Uint64()
+	}
+
+	// Whole rounds go straight into dst
+	round := 4 * m.lanes
+	n := (len(dst) - i) / round * round
+	multiRounds(&m.state, m.lanes, dst[i:i+n])
+	i += n
+
+	for ; i < len(dst); i++ {
+		dst[i] = m.Uint64()
+	}
+}
+
+// Read implements io.Reader with the words of the interleaved stream in
+// little-endian order. Like RNG.Read, leftover bytes of a word are kept
+// for the next Read, so the byte stream doesn't depend on the chunking.
+func (m *MultiRNG) Read(p []byte) (n int, err error) {
+	readWords(p, &m.tail, &m.ntail, m.Uint64, func(p []byte) int {
+		// Finish the current round, then whole rounds go straight into p
+		i := 0
+		for ; len(p)-i >= 8 && m.pos < 4*m.lanes; i += 8 {
+			binary.LittleEndian.PutUint64(p[i:], m.Uint64())
+		}
+		round := 32 * m.lanes
+		whole := (len(p) - i) / round * round
+		multiRoundsBytes(&m.state, m.lanes, p[i:i+whole])
+		return i + whole
+	})
+	return len(p), nil
+}
+
+// multiRounds fills dst, whose length is a multiple of 4*lanes, with
+// whole rounds and steps every lane once per round
+//
+// The lanes are independent, so this runs each lane through all the
+// rounds with its state in registers, and writes its words at a stride.
+func multiRounds(s *[4][8]uint64, lanes int, dst []uint64) {
+	round := 4 * lanes
+	if len(dst) > 0 && multiRoundsAsm(s, lanes, unsafe.Pointer(&dst[0]), len(dst)/round) {
+		return
+	}
+	for l := 0; l < lanes; l++ {
+		s0, s1, s2, s3 := s[0][l], s[1][l], s[2][l], s[3][l]
+		for i := l; i < len(dst); i += round {
+			d := dst[i : i+round-lanes+1]
+			d[0] = mix(s0)
+			d[lanes] = mix(s1)
+			d[2*lanes] = mix(s2)
+			d[3*lanes] = mix(s3)
+			// Same as RNG.step
+			s0, s1, s2, s3 =
+				((s0>>1)|(s3<<63))^(s0|(s0<<1)|(s1>>63)),
+				((s1>>1)|(s0<<63))^(s1|(s1<<1)|(s2>>63)),
+				((s2>>1)|(s1<<63))^(s2|(s2<<1)|(s3>>63)),
+				((s3>>1)|(s2<<63))^(s3|(s3<<1)|(s0>>63))
+		}
+		s[0][l], s[1][l], s[2][l], s[3][l] = s0, s1, s2, s3
+	}
+}
+
+// multiRoundsBytes is multiRounds writing little-endian bytes
+func multiRoundsBytes(s *[4][8]uint64, lanes int, dst []byte) {
+	round, stride := 32*lanes, 8*lanes
+	if len(dst) > 0 && multiRoundsAsm(s, lanes, unsafe.Pointer(&dst[0]), len(dst)/round) {
+		return
+	}
+	for l := 0; l < lanes; l++ {
+		s0, s1, s2, s3 := s[0][l], s[1][l], s[2][l], s[3][l]
+		for i := 8 * l; i < len(dst); i += round {
+			d := dst[i : i+round-stride+8]
+			binary.LittleEndian.PutUint64(d, mix(s0))
+			binary.LittleEndian.PutUint64(d[stride:], mix(s1))
+			binary.LittleEndian.PutUint64(d[2*stride:], mix(s2))
+			binary.LittleEndian.PutUint64(d[3*stride:], mix(s3))
+			s0, s1, s2, s3 =
+				((s0>>1)|(s3<<63))^(s0|(s0<<1)|(s1>>63)),
+				((s1>>1)|(s0<<63))^(s1|(s1<<1)|(s2>>63)),
+				((s2>>1)|(s1<<63))^(s2|(s2<<1)|(s3>>63)),
+				((s3>>1)|(s2<<63))^(s3|(s3<<1)|(s0>>63))
+		}
+		s[0][l], s[1][l], s[2][l], s[3][l] = s0, s1, s2, s3
+	}
+}
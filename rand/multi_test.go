@@ -0,0 +1,113 @@
+package rand
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// multiReference returns the first n words of NewMulti(seed, lanes),
+// interleaved by hand from the lanes' own RNG streams
+func multiReference(seed uint64, lanes, n int) []uint64 {
+	parent := New(seed)
+	rngs := make([]*RNG, lanes)
+	for l := range rngs {
+		rngs[l] = parent.Split()
+	}
+	out := make([]uint64, 0, n+4*lanes)
+	for len(out) < n {
+		var round [4][8]uint64
+		for l, r := range rngs {
+			for w := 0; w < 4; w++ {
+				round[w][l] = r.Uint64()
+			}
+		}
+		for w := 0; w < 4; w++ {
+			out = append(out, round[w][:lanes]...)
+		}
+	}
+	return out[:n]
+}
+
+func TestMultiInterleaving(t *testing.T) {
+	for _, lanes := range []int{4, 8} {
+		want := multiReference(5, lanes, 1000)
+		m := NewMulti(5, lanes)
+		for i, w := range want {
+			if got := m.Uint64(); got != w {
+				t.Fatalf("lanes %d: word %d = %#x, want %#x", lanes, i, got, w)
+			}
+		}
+	}
+}
+
+func TestMultiFillUint64(t *testing.T) {
+	for _, lanes := range []int{4, 8} {
+		for _, skip := range []int{0, 1, 15, 16, 31} {
+			for _, n := range []int{0, 1, 16, 17, 32, 1000} {
+				want := multiReference(9, lanes, skip+n+1)
+				m := NewMulti(9, lanes)
+				for range skip {
+					m.Uint64()
+				}
+				got := make([]uint64, n)
+				m.FillUint64(got)
+				for i := range got {
+					if got[i] != want[skip+i] {
+						t.Fatalf("lanes %d, skip %d, n %d: word %d = %#x, want %#x",
+							lanes, skip, n, i, got[i], want[skip+i])
+					}
+				}
+				if got, w := m.Uint64(), want[skip+n]; got != w {
+					t.Fatalf("lanes %d, skip %d, n %d: next word = %#x, want %#x", lanes, skip, n, got, w)
+				}
+			}
+		}
+	}
+}
+
+func TestMultiRead(t *testing.T) {
+	const total = 5000
+	for _, lanes := range []int{4, 8} {
+		words := multiReference(3, lanes, total/8+1)
+		want := make([]byte, 0, 8*len(words))
+		for _, w := range words {
+			want = binary.LittleEndian.AppendUint64(want, w)
+		}
+		want = want[:total]
+
+		for _, chunks := range [][]int{{total}, {1}, {3, 5}, {8}, {13, 0, 2}, {257}, {4096, 7}} {
+			got := readChunked(NewMulti(3, lanes), total, chunks)
+			if !bytes.Equal(got, want) {
+				t.Errorf("lanes %d, chunks %v: byte stream differs", lanes, chunks)
+			}
+		}
+	}
+}
+
+func TestMultiLanesDiffer(t *testing.T) {
+	// Same seed, different lane counts: the first four lanes agree
+	a, b := multiReference(1, 4, 64), multiReference(1, 8, 128)
+	for r := 0; r < 4; r++ {
+		for w := 0; w < 4; w++ {
+			for l := 0; l < 4; l++ {
+				if a[16*r+4*w+l] != b[32*r+8*w+l] {
+					t.Fatalf("round %d word %d lane %d differs between 4 and 8 lanes", r, w, l)
+				}
+			}
+		}
+	}
+	if NewMulti(1, 4).Lanes() != 4 || NewMulti(1, 8).Lanes() != 8 {
+		t.Error("Lanes does not report the lane count")
+	}
+	for _, lanes := range []int{0, 1, 2, 3, 5, 16} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewMulti(1, %d) did not panic", lanes)
+				}
+			}()
+			NewMulti(1, lanes)
+		}()
+	}
+}
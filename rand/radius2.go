@@ -0,0 +1,83 @@
+package rand
+
+// This file contains the radius-2 variant of Rule 30. Each cell sees five
+// neighbours (two left, itself, two right) and keeps Rule 30's shape,
+// "left XOR (center OR right)", with the left side widened by XOR and the
+// right side by OR:
+//
+//	new_bit = left2 XOR left1 XOR (center OR right1 OR right2)
+//
+// Like Rule 30 it is permutive in its leftmost input, the property that
+// makes the rule chaotic. Borders now carry two bits from each neighbouring
+// word, still pre-computed once per step.
+
+// Radius2RNG runs radius-2 Rule 30 on a 256-bit ring
+type Radius2RNG struct {
+	state [4]uint64
+	pos   int
+	tail  uint64
+	ntail int
+}
+
+// NewRadius2 creates a new radius-2 Rule 30 generator from a seed
+func NewRadius2(seed uint64) *Radius2RNG {
+	r := &Radius2RNG{}
+	r.Reseed(seed)
+	return r
+}
+
+// Reseed resets the generator to the state NewRadius2(seed) would produce
+func (r *Radius2RNG) Reseed(seed uint64) {
+	for i := range r.state {
+		r.state[i] = seed ^ seedConst[i]
+	}
+	r.pos = 0
+	r.tail, r.ntail = 0, 0
+	for i := 0; i < 16; i++ {
+		r.step()
+	}
+}
+
+// step applies radius-2 Rule 30 to the 256-bit ring
+//
+//go:noinline
+func (r *Radius2RNG) step() {
+	s0, s1, s2, s3 := r.state[0], r.state[1], r.state[2], r.state[3]
+
+	// Pre-compute the two border bits on each side of every word
+	lo0, lo1, lo2, lo3 := s0&3, s1&3, s2&3, s3&3
+	hi0, hi1, hi2, hi3 := s0>>62, s1>>62, s2>>62, s3>>62
+
+	// Word 0: left from s3's low bits, right from s1's high bits
+	r.state[0] = ((s0 >> 2) | (lo3 << 62)) ^ ((s0 >> 1) | (lo3 << 63)) ^
+		(s0 | (s0 << 1) | (hi1 >> 1) | (s0 << 2) | hi1)
+
+	// Word 1: left from s0's low bits, right from s2's high bits
+	r.state[1] = ((s1 >> 2) | (lo0 << 62)) ^ ((s1 >> 1) | (lo0 << 63)) ^
+		(s1 | (s1 << 1) | (hi2 >> 1) | (s1 << 2) | hi2)
+
+	// Word 2: left from s1's low bits, right from s3's high bits
+	r.state[2] = ((s2 >> 2) | (lo1 << 62)) ^ ((s2 >> 1) | (lo1 << 63)) ^
+		(s2 | (s2 << 1) | (hi3 >> 1) | (s2 << 2) | hi3)
+
+	// Word 3: left from s2's low bits, right from s0's high bits
+	r.state[3] = ((s3 >> 2) | (lo2 << 62)) ^ ((s3 >> 1) | (lo2 << 63)) ^
+		(s3 | (s3 << 1) | (hi0 >> 1) | (s3 << 2) | hi0)
+}
+
+// Uint64 returns a random uint64
+func (r *Radius2RNG) Uint64() uint64 {
+	if r.pos == 4 {
+		r.step()
+		r.pos = 0
+	}
+	out := mix(r.state[r.pos])
+	r.pos++
+	return out
+}
+
+// Read implements io.Reader like RNG.Read
+func (r *Radius2RNG) Read(buf []byte) (n int, err error) {
+	readWords(buf, &r.tail, &r.ntail, r.Uint64, nil)
+	return len(buf), nil
+}
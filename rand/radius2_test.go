@@ -0,0 +1,62 @@
+package rand
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestRadius2StepMatchesReference(t *testing.T) {
+	rule := func(nb []uint64) uint64 {
+		return nb[0] ^ nb[1] ^ (nb[2] | nb[3] | nb[4])
+	}
+	src := New(8)
+	var r Radius2RNG
+	for trial := 0; trial < 100; trial++ {
+		for i := range r.state {
+			r.state[i] = src.Uint64()
+		}
+		want := referenceStepRadius(r.state[:], 2, rule)
+		r.step()
+		for i := range want {
+			if r.state[i] != want[i] {
+				t.Fatalf("trial %d word %d: %#x, want %#x", trial, i, r.state[i], want[i])
+			}
+		}
+	}
+}
+
+func TestRadius2Stream(t *testing.T) {
+	a, b := NewRadius2(5), NewRadius2(5)
+	whole := make([]byte, 99)
+	a.Read(whole)
+	var parts []byte
+	for _, n := range []int{3, 5, 8, 83} {
+		buf := make([]byte, n)
+		b.Read(buf)
+		parts = append(parts, buf...)
+	}
+	if !bytes.Equal(whole, parts) {
+		t.Fatal("Radius2RNG byte stream depends on Read chunking")
+	}
+
+	// The variant must actually differ from radius-1
+	if NewRadius2(5).Uint64() == New(5).Uint64() {
+		t.Fatal("radius-2 and radius-1 streams coincide")
+	}
+}
+
+func TestRadiusQualityComparison(t *testing.T) {
+	// A quick uniformity figure for both variants; run with -v to compare.
+	// TestU01 via "ring30mix raw --gen=radius2" is the real comparison.
+	for name, r := range map[string]io.Reader{
+		"radius-1": New(1),
+		"radius-2": NewRadius2(1),
+	} {
+		chi2 := chiSquareBytes(t, r, 400_000)
+		t.Logf("%s: byte chi-square %.1f", name, chi2)
+		if chi2 > chiSquare255 {
+			t.Errorf("%s byte chi-square = %.1f", name, chi2)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package rand
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+)
+
+// readChunked reads total bytes from rng using the given chunk sizes in turn
+func readChunked(rng io.Reader, total int, chunks []int) []byte {
+	out := make([]byte, 0, total)
+	for i := 0; len(out) < total; i++ {
+		n := min(chunks[i%len(chunks)], total-len(out))
+		buf := make([]byte, n)
+		rng.Read(buf)
+		out = append(out, buf...)
+	}
+	return out
+}
+
+func TestReadChunkingInvariant(t *testing.T) {
+	const total = 1000
+	want := make([]byte, total)
+	New(2).Read(want)
+
+	for _, chunks := range [][]int{{1}, {3, 5}, {7}, {8}, {9, 1, 16}, {13, 0, 2}, {31}, {999}} {
+		got := readChunked(New(2), total, chunks)
+		if !bytes.Equal(got, want) {
+			t.Errorf("chunks %v: byte stream differs", chunks)
+		}
+	}
+}
+
+func TestReadMatchesUint64(t *testing.T) {
+	// Whole words of the byte stream are the little-endian Uint64 outputs
+	rng, ref := New(4), New(4)
+	buf := readChunked(rng, 80, []int{3, 5})
+	for i := 0; i < 10; i++ {
+		if got, want := binary.LittleEndian.Uint64(buf[8*i:]), ref.Uint64(); got != want {
+			t.Fatalf("word %d: %#x, want %#x", i, got, want)
+		}
+	}
+}
+
+func TestSkipBytesChunked(t *testing.T) {
+	for _, skip := range []uint64{0, 1, 3, 8, 13, 100} {
+		a, b := New(6), New(6)
+		a.Read(make([]byte, 3))
+		b.Read(make([]byte, 3))
+		a.SkipBytes(skip)
+		readChunked(b, int(skip)+1, []int{5}) // +1 so a zero skip still reads
+		a.Read(make([]byte, 1))
+
+		x, y := make([]byte, 21), make([]byte, 21)
+		a.Read(x)
+		b.Read(y)
+		if !bytes.Equal(x, y) {
+			t.Errorf("SkipBytes(%d) after a 3-byte Read diverges", skip)
+		}
+	}
+}
+
+func TestMarshalPreservesReadTail(t *testing.T) {
+	rng := New(10)
+	rng.Read(make([]byte, 5))
+	data, _ := rng.MarshalBinary()
+	var restored RNG
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	x, y := make([]byte, 19), make([]byte, 19)
+	rng.Read(x)
+	restored.Read(y)
+	if !bytes.Equal(x, y) {
+		t.Fatal("restored generator lost buffered Read bytes")
+	}
+}
+
+// failingWriter accepts limit bytes, then fails
+type failingWriter struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+var errWriterFull = errors.New("writer full")
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	room := w.limit - w.buf.Len()
+	if len(p) <= room {
+		return w.buf.Write(p)
+	}
+	w.buf.Write(p[:room])
+	return room, errWriterFull
+}
+
+func TestWriteTo(t *testing.T) {
+	const limit = 100_000
+	w := &failingWriter{limit: limit}
+	n, err := io.Copy(w, New(12))
+	if !errors.Is(err, errWriterFull) {
+		t.Fatalf("io.Copy returned %v, want errWriterFull", err)
+	}
+	if n != limit {
+		t.Fatalf("io.Copy wrote %d bytes, want %d", n, limit)
+	}
+	want := make([]byte, limit)
+	New(12).Read(want)
+	if !bytes.Equal(w.buf.Bytes(), want) {
+		t.Fatal("WriteTo stream differs from Read")
+	}
+}
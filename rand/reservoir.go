@@ -0,0 +1,125 @@
+package rand
+
+import (
+	"container/heap"
+	"iter"
+	"math"
+)
+
+// This file contains reservoir samplers, which pick k items from a
+// sequence of unknown length in one pass and O(k) memory.
+
+// Reservoir returns k items chosen uniformly without replacement from seq
+// Uses Algorithm L of K.-H. Li, "Reservoir-Sampling Algorithms of Time
+// Complexity O(n(1+log(N/n)))", ACM TOMS 20(4), 1994, which draws random
+// numbers only for the O(k log(N/k)) items that enter the reservoir. If
+// seq yields fewer than k items, all of them are returned. The order of
+// the result is not random.
+// Panics if k < 0
+func Reservoir[T any](r *RNG, seq iter.Seq[T], k int) []T {
+	if k < 0 {
+		panic("invalid argument to Reservoir")
+	}
+	out := make([]T, 0, k)
+	if k == 0 {
+		return out
+	}
+	var i, next int64
+	var w float64
+	for v := range seq {
+		switch {
+		case i < int64(k):
+			out = append(out, v)
+			if i == int64(k)-1 {
+				w = math.Exp(math.Log(r.Float64Open()) / float64(k))
+				next = i + reservoirSkip(r, w)
+			}
+		case i == next:
+			out[r.uint64n(uint64(k))] = v
+			w *= math.Exp(math.Log(r.Float64Open()) / float64(k))
+			next = i + reservoirSkip(r, w)
+		}
+		i++
+	}
+	return out
+}
+
+// reservoirSkip returns the distance to the next item that enters an
+// Algorithm L reservoir whose threshold is w
+func reservoirSkip(r *RNG, w float64) int64 {
+	gap := math.Floor(math.Log(r.Float64Open())/math.Log1p(-w)) + 1
+	if !(gap < 1<<62) {
+		return 1 << 62
+	}
+	return int64(gap)
+}
+
+// WeightedReservoir returns k items chosen without replacement from seq,
+// which yields (item, weight) pairs, with probability proportional to
+// weight at each selection
+// Uses A-ExpJ of P. S. Efraimidis and P. G. Spirakis, "Weighted Random
+// Sampling with a Reservoir", Information Processing Letters 97(5), 2006:
+// each item gets the key u^(1/weight), the k largest keys win, and
+// exponential jumps skip items that can't enter. Items with weight 0 are
+// never chosen. If fewer than k items have a positive weight, all of
+// them are returned. The order of the result is not random.
+// Panics if a weight is negative or NaN, or if k < 0
+func WeightedReservoir[T any](r *RNG, seq iter.Seq2[T, float64], k int) []T {
+	if k < 0 {
+		panic("invalid argument to WeightedReservoir")
+	}
+	h := &keyHeap[T]{}
+	var jump float64 // remaining weight to skip before the next insertion
+	for v, w := range seq {
+		if !(w >= 0) {
+			panic("invalid weight in WeightedReservoir")
+		}
+		if w == 0 || k == 0 {
+			continue
+		}
+		if len(h.items) < k {
+			// Keys are stored as log(u)/weight to avoid underflow
+			heap.Push(h, keyed[T]{v, math.Log(r.Float64Open()) / w})
+			if len(h.items) == k {
+				jump = math.Log(r.Float64Open()) / h.items[0].key
+			}
+			continue
+		}
+		if jump -= w; jump > 0 {
+			continue
+		}
+		// The item enters with a key drawn uniformly above the threshold:
+		// u in (t^w, 1) where t is the smallest key
+		tw := math.Exp(h.items[0].key * w)
+		u := tw + r.Float64Open()*(1-tw)
+		h.items[0] = keyed[T]{v, math.Log(u) / w}
+		heap.Fix(h, 0)
+		jump = math.Log(r.Float64Open()) / h.items[0].key
+	}
+	out := make([]T, len(h.items))
+	for i, it := range h.items {
+		out[i] = it.item
+	}
+	return out
+}
+
+// keyed is an item with its A-ExpJ key
+type keyed[T any] struct {
+	item T
+	key  float64
+}
+
+// keyHeap is a min-heap of keyed items
+type keyHeap[T any] struct {
+	items []keyed[T]
+}
+
+func (h *keyHeap[T]) Len() int           { return len(h.items) }
+func (h *keyHeap[T]) Less(i, j int) bool { return h.items[i].key < h.items[j].key }
+func (h *keyHeap[T]) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *keyHeap[T]) Push(x any)         { h.items = append(h.items, x.(keyed[T])) }
+func (h *keyHeap[T]) Pop() any {
+	x := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return x
+}
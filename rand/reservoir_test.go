@@ -0,0 +1,115 @@
+package rand
+
+import (
+	"iter"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestReservoir(t *testing.T) {
+	const n, k, trials = 200, 5, 40_000
+	rng := New(11)
+	included := make([]int, n)
+	for i := 0; i < trials; i++ {
+		got := Reservoir(rng, intRange(n), k)
+		if len(got) != k {
+			t.Fatalf("Reservoir returned %d items, want %d", len(got), k)
+		}
+		for _, v := range got {
+			included[v]++
+		}
+	}
+	// 199 degrees of freedom, p = 0.001
+	if x2 := chiSquare(included, trials*k); x2 > 266.4 {
+		t.Errorf("Reservoir inclusion chi-square = %.1f", x2)
+	}
+
+	got := Reservoir(rng, intRange(3), 10)
+	slices.Sort(got)
+	if !slices.Equal(got, []int{0, 1, 2}) {
+		t.Fatalf("short sequence: got %v", got)
+	}
+	if got := Reservoir(rng, intRange(3), 0); len(got) != 0 {
+		t.Fatalf("k = 0: got %v", got)
+	}
+}
+
+func TestWeightedReservoir(t *testing.T) {
+	// Inclusion probabilities of weighted sampling without replacement,
+	// enumerated for k = 2
+	weights := []float64{1, 0, 2, 3, 0.5, 4}
+	const k, trials = 2, 200_000
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+	want := make([]float64, len(weights))
+	for i, wi := range weights {
+		for j, wj := range weights {
+			if i != j && wi > 0 && wj > 0 {
+				p := wi / total * wj / (total - wi)
+				want[i] += p
+				want[j] += p
+			}
+		}
+	}
+
+	rng := New(12)
+	counts := make([]int, len(weights))
+	for i := 0; i < trials; i++ {
+		for _, v := range WeightedReservoir(rng, slices.All(weights), k) {
+			counts[v]++
+		}
+	}
+	if counts[1] != 0 {
+		t.Fatalf("zero-weight item chosen %d times", counts[1])
+	}
+	for i, c := range counts {
+		if want[i] == 0 {
+			continue
+		}
+		mean := want[i] * trials
+		sd := math.Sqrt(mean * (1 - want[i]))
+		if math.Abs(float64(c)-mean) > 5*sd {
+			t.Errorf("item %d included %d times, want %.0f", i, c, mean)
+		}
+	}
+
+	got := WeightedReservoir(rng, slices.All(weights), 10)
+	slices.Sort(got)
+	if !slices.Equal(got, []int{0, 2, 3, 4, 5}) {
+		t.Fatalf("short sequence: got %v", got)
+	}
+}
+
+func TestWeightedReservoirLongStream(t *testing.T) {
+	// Exercises the jumps: with k = 1 each item wins with probability
+	// weight / total, here proportional to i % 8
+	const n, trials = 4000, 8000
+	weights := make([]float64, n)
+	for i := range weights {
+		weights[i] = float64(i % 8)
+	}
+	rng := New(13)
+	counts := make([]int, 8)
+	for i := 0; i < trials; i++ {
+		counts[WeightedReservoir(rng, slices.All(weights), 1)[0]%8]++
+	}
+	for r, c := range counts {
+		mean := float64(r) / 28 * trials
+		if math.Abs(float64(c)-mean) > 5*math.Sqrt(mean+1) {
+			t.Errorf("class %d chosen %d times, want %.0f", r, c, mean)
+		}
+	}
+}
+
+func intRange(n int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 0; i < n; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
@@ -1,32 +1,56 @@
+// Package rand generates pseudo-random numbers from a Rule 30 cellular
+// automaton on a 256-bit ring, with a math/rand compatible method set.
+//
+// New(seed) follows LatestVersion, currently V2. V2 changed NormFloat64
+// and ExpFloat64 to ziggurat samplers, so their values for a given seed
+// differ from earlier releases, which used the polar method and -log(u).
+// Uint64, Read, Int, Intn and Float64 are unchanged. Use NewVersion(seed,
+// V1) or SetVersion(V1) to keep the old NormFloat64 and ExpFloat64 values.
 package rand
 
 import (
 	"encoding/binary"
+	"io"
 	"math/bits"
+	"unsafe"
 )
 
 // RNG implements a 1D cellular automaton (Rule 30) on a 256-bit ring
 type RNG struct {
 	state [4]uint64 // 256-bit state (4 × 64-bit words)
 	pos   int       // current position for output (0-3)
+	tail  uint64    // unread bytes of the last word used by Read
+	ntail int       // number of bytes left in tail (0-7)
+
+	version Version // 0 for LatestVersion
+}
+
+// seedConst holds the constants XORed into each word of a seed
+var seedConst = [4]uint64{
+	0,
+	0x9e3779b97f4a7c15,
+	0x3c6ef372fe94f82a,
+	0x78dde6e5fd29f054,
 }
 
 // New creates a new Rule 30 RNG from a seed
 func New(seed uint64) *RNG {
-	rng := &RNG{
-		state: [4]uint64{
-			seed,
-			seed ^ 0x9e3779b97f4a7c15,
-			seed ^ 0x3c6ef372fe94f82a,
-			seed ^ 0x78dde6e5fd29f054,
-		},
-		pos: 0,
+	rng := &RNG{}
+	rng.Reseed(seed)
+	return rng
+}
+
+// Reseed resets the generator to the state New(seed) would produce
+func (r *RNG) Reseed(seed uint64) {
+	for i := range r.state {
+		r.state[i] = seed ^ seedConst[i]
 	}
+	r.pos = 0
+	r.tail, r.ntail = 0, 0
 	// Run a few steps to mix the initial state
 	for i := 0; i < 16; i++ {
-		rng.step()
+		r.step()
 	}
-	return rng
 }
 
 // step applies radius-1 Rule 30 to the 256-bit ring
@@ -72,31 +96,78 @@ func (r *RNG) Uint64() uint64 {
 		r.step()
 		r.pos = 0
 	}
-	out := mix(r.state[r.pos])
+	w := r.state[r.pos]
 	r.pos++
-	return out
+	return mix(w)
 }
 
 // Read implements io.Reader interface
+// Bytes left over from a partially used word are kept for the next Read,
+// so the byte stream is the same however the reads are chunked. Uint64 and
+// the methods built on it draw whole words and don't consume these bytes.
 func (r *RNG) Read(buf []byte) (n int, err error) {
-	i := 0
-	limit := len(buf)
+	readWords(buf, &r.tail, &r.ntail, r.Uint64, func(p []byte) int {
+		// Whole steps at once, if there is an assembly kernel for this CPU
+		i := 0
+		for ; r.pos < 4 && len(p)-i >= 8; i += 8 {
+			binary.LittleEndian.PutUint64(p[i:], r.Uint64())
+		}
+		if blocks := (len(p) - i) / 32; blocks > 0 && stepMixBlocks(&r.state, unsafe.Pointer(&p[i]), blocks) {
+			i += 32 * blocks
+		}
+		// The rest here too, with Uint64 called directly
+		for ; len(p)-i >= 8; i += 8 {
+			binary.LittleEndian.PutUint64(p[i:], r.Uint64())
+		}
+		return i
+	})
+	return len(buf), nil
+}
 
-	// Handle 8-byte chunks
-	for limit-i >= 8 {
-		val := r.Uint64()
-		binary.LittleEndian.PutUint64(buf[i:], val)
-		i += 8
+// readWords fills buf with the words of next in little-endian order, for
+// the Read methods of all generators. Bytes left over from the previous
+// call, tail with ntail of them valid, come first, and the unused bytes of
+// the last word are stored back there. If bulk is not nil, it is given the
+// rest of buf after the drained bytes and may fill a prefix of it with whole
+// words of the same stream; it returns the number of bytes it wrote.
+func readWords(buf []byte, tail *uint64, ntail *int, next func() uint64, bulk func([]byte) int) {
+	i := 0
+	for *ntail > 0 && i < len(buf) {
+		buf[i] = byte(*tail)
+		*tail >>= 8
+		*ntail--
+		i++
 	}
-
-	// Handle remaining tail bytes
-	if rem := limit - i; rem > 0 {
-		val := r.Uint64()
+	if bulk != nil && len(buf)-i >= 8 {
+		i += bulk(buf[i:])
+	}
+	for ; len(buf)-i >= 8; i += 8 {
+		binary.LittleEndian.PutUint64(buf[i:], next())
+	}
+	if rem := len(buf) - i; rem > 0 {
+		val := next()
 		for j := 0; j < rem; j++ {
 			buf[i+j] = byte(val)
 			val >>= 8
 		}
+		*tail, *ntail = val, 8-rem
 	}
+}
 
-	return limit, nil
+// WriteTo implements io.WriterTo, so io.Copy(w, rng) streams without an
+// intermediate copy. The stream is endless: WriteTo only returns when w
+// fails, with the number of bytes w accepted.
+func (r *RNG) WriteTo(w io.Writer) (n int64, err error) {
+	buf := make([]byte, 32<<10)
+	for {
+		r.Read(buf)
+		m, err := w.Write(buf)
+		n += int64(m)
+		if err != nil {
+			return n, err
+		}
+		if m != len(buf) {
+			return n, io.ErrShortWrite
+		}
+	}
 }
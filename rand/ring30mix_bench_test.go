@@ -43,6 +43,268 @@ func BenchmarkRing30Mix_Uint64(b *testing.B) {
 	}
 }
 
+func BenchmarkRing30Mix_Mixers(b *testing.B) {
+	for _, m := range StdMixers() {
+		b.Run(m.String(), func(b *testing.B) {
+			rng := NewMixed(42, m)
+			for i := 0; i < b.N; i++ {
+				_ = rng.Uint64()
+			}
+		})
+	}
+}
+
+func BenchmarkRing30Mix_Intn(b *testing.B) {
+	rng := New(42)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = rng.Intn(1000)
+	}
+}
+
+func BenchmarkRing30Mix_IntN(b *testing.B) {
+	rng := New(42)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = rng.IntN(1000)
+	}
+}
+
+// NormFloat64 and ExpFloat64 under each stream version: V1 is the polar
+// method and -log(u), V2 the ziggurat samplers
+
+func BenchmarkRing30Mix_NormFloat64(b *testing.B) {
+	for _, v := range []Version{V1, V2} {
+		b.Run(v.String(), func(b *testing.B) {
+			rng := NewVersion(42, v)
+			for i := 0; i < b.N; i++ {
+				_ = rng.NormFloat64()
+			}
+		})
+	}
+}
+
+func BenchmarkRing30Mix_ExpFloat64(b *testing.B) {
+	for _, v := range []Version{V1, V2} {
+		b.Run(v.String(), func(b *testing.B) {
+			rng := NewVersion(42, v)
+			for i := 0; i < b.N; i++ {
+				_ = rng.ExpFloat64()
+			}
+		})
+	}
+}
+
+// Skipping and generating 1M outputs, reported as output bytes per second
+const skipOutputs = 1 << 20
+
+func BenchmarkRing30Mix_Advance1M(b *testing.B) {
+	rng := New(42)
+	b.SetBytes(8 * skipOutputs)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rng.Advance(skipOutputs)
+	}
+}
+
+func BenchmarkRing30Mix_Generate1M(b *testing.B) {
+	rng := New(42)
+	b.SetBytes(8 * skipOutputs)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < skipOutputs; j++ {
+			_ = rng.Uint64()
+		}
+	}
+}
+
+// Multi-lane generator, compare with BenchmarkRing30Mix_Read32KB
+
+func BenchmarkMulti4_Read32KB(b *testing.B) {
+	benchmarkMultiRead(b, 4)
+}
+
+func BenchmarkMulti8_Read32KB(b *testing.B) {
+	benchmarkMultiRead(b, 8)
+}
+
+func benchmarkMultiRead(b *testing.B, lanes int) {
+	rng := NewMulti(12345, lanes)
+	buf := make([]byte, 32<<10)
+	b.SetBytes(int64(len(buf)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := rng.Read(buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMulti8_FillUint64(b *testing.B) {
+	rng := NewMulti(42, 8)
+	dst := make([]uint64, 4096)
+	b.SetBytes(int64(8 * len(dst)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rng.FillUint64(dst)
+	}
+}
+
+// Filling 1M-element slices, bulk methods vs scalar loops
+
+func BenchmarkFill1M(b *testing.B) {
+	const n = 1 << 20
+	u64 := make([]uint64, n)
+	f64 := make([]float64, n)
+	f32 := make([]float32, n)
+	ints := make([]int, n)
+	rng := New(42)
+	cases := []struct {
+		name   string
+		bulk   func()
+		scalar func()
+	}{
+		{"Uint64", func() { rng.FillUint64(u64) }, func() {
+			for i := range u64 {
+				u64[i] = rng.Uint64()
+			}
+		}},
+		{"Float64", func() { rng.FillFloat64(f64) }, func() {
+			for i := range f64 {
+				f64[i] = rng.Float64()
+			}
+		}},
+		{"Float32", func() { rng.FillFloat32(f32) }, func() {
+			for i := range f32 {
+				f32[i] = rng.Float32()
+			}
+		}},
+		{"Normal", func() { rng.FillNormal(f64) }, func() {
+			for i := range f64 {
+				f64[i] = rng.NormFloat64()
+			}
+		}},
+		{"IntN", func() { rng.FillIntN(ints, 1000) }, func() {
+			for i := range ints {
+				ints[i] = rng.IntN(1000)
+			}
+		}},
+	}
+	for _, c := range cases {
+		b.Run(c.name+"/bulk", func(b *testing.B) {
+			b.SetBytes(n * 8)
+			for i := 0; i < b.N; i++ {
+				c.bulk()
+			}
+		})
+		b.Run(c.name+"/scalar", func(b *testing.B) {
+			b.SetBytes(n * 8)
+			for i := 0; i < b.N; i++ {
+				c.scalar()
+			}
+		})
+	}
+}
+
+// Ring width comparison: same rule and mixer, different ring sizes
+
+func BenchmarkRing128_Uint64(b *testing.B) {
+	rng := NewRing128(42)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = rng.Uint64()
+	}
+}
+
+func BenchmarkRing512_Uint64(b *testing.B) {
+	rng := NewRing512(42)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = rng.Uint64()
+	}
+}
+
+func BenchmarkRing1024_Uint64(b *testing.B) {
+	rng := NewRing1024(42)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = rng.Uint64()
+	}
+}
+
+func BenchmarkRing128_Read32KB(b *testing.B) {
+	rng := NewRing128(12345)
+	buf := make([]byte, 32<<10)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rng.Read(buf)
+	}
+}
+
+func BenchmarkRing512_Read32KB(b *testing.B) {
+	rng := NewRing512(12345)
+	buf := make([]byte, 32<<10)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rng.Read(buf)
+	}
+}
+
+func BenchmarkRing1024_Read32KB(b *testing.B) {
+	rng := NewRing1024(12345)
+	buf := make([]byte, 32<<10)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rng.Read(buf)
+	}
+}
+
+// Rule variants on the 256-bit ring
+
+func BenchmarkRadius2_Uint64(b *testing.B) {
+	rng := NewRadius2(42)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = rng.Uint64()
+	}
+}
+
+func BenchmarkRadius2_Read32KB(b *testing.B) {
+	rng := NewRadius2(12345)
+	buf := make([]byte, 32<<10)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rng.Read(buf)
+	}
+}
+
+func BenchmarkRuleRNG30_Uint64(b *testing.B) {
+	rng := NewRule(30, 42)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = rng.Uint64()
+	}
+}
+
+func BenchmarkCounter_Block(b *testing.B) {
+	c := NewCounter([32]byte{1})
+	b.SetBytes(32)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = c.Block(uint64(i))
+	}
+}
+
+func BenchmarkCounter_Fill1M(b *testing.B) {
+	c := NewCounter([32]byte{1})
+	dst := make([]uint64, 1<<20)
+	b.SetBytes(8 << 20)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Fill(dst, uint64(i)<<18)
+	}
+}
+
 // ====================
 // math/rand Benchmarks
 // ====================
@@ -81,6 +343,13 @@ func BenchmarkMathRand_Uint64(b *testing.B) {
 // math/rand/v2 PCG Benchmarks
 // ============================
 
+func BenchmarkMathRandV2PCG_NormFloat64(b *testing.B) {
+	rng := mathrandv2.New(mathrandv2.NewPCG(42, 1024))
+	for i := 0; i < b.N; i++ {
+		_ = rng.NormFloat64()
+	}
+}
+
 func BenchmarkMathRandV2PCG_Read32KB(b *testing.B) {
 	rng := mathrandv2.NewPCG(12345, 67890)
 	buf := make([]byte, 32<<10)
@@ -153,6 +422,83 @@ func BenchmarkMathRandV2ChaCha8_Uint64(b *testing.B) {
 	}
 }
 
+// ========================================
+// Package-level functions under contention
+// ========================================
+
+func BenchmarkGlobalRing30Mix_Uint64Parallel(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = Uint64()
+		}
+	})
+}
+
+func BenchmarkGlobalMathRandV2_Uint64Parallel(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = mathrandv2.Uint64()
+		}
+	})
+}
+
+func BenchmarkGlobalRing30Mix_IntNParallel(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = IntN(1000)
+		}
+	})
+}
+
+func BenchmarkGlobalMathRandV2_IntNParallel(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = mathrandv2.IntN(1000)
+		}
+	})
+}
+
+func BenchmarkGlobalRing30Mix_Read1KBParallel(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		buf := make([]byte, 1<<10)
+		for pb.Next() {
+			Read(buf)
+		}
+	})
+}
+
+func BenchmarkLocked_Uint64Parallel(b *testing.B) {
+	l := NewLocked(42)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = l.Uint64()
+		}
+	})
+}
+
+func BenchmarkPool_Uint64Parallel(b *testing.B) {
+	p := NewPool(42)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			r := p.Get()
+			_ = r.Uint64()
+			p.Put(r)
+		}
+	})
+}
+
+func BenchmarkPool_Read1KBParallel(b *testing.B) {
+	p := NewPool(42)
+	b.RunParallel(func(pb *testing.PB) {
+		buf := make([]byte, 1<<10)
+		for pb.Next() {
+			r := p.Get()
+			r.Read(buf)
+			p.Put(r)
+		}
+	})
+}
+
 // ====================
 // crypto/rand Benchmarks
 // ====================
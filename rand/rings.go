@@ -0,0 +1,16 @@
+package rand
+
+//go:generate go run gen_rings.go
+
+// This file contains the shared parts of the Ring128, Ring512 and Ring1024
+// generators in rings_gen.go. They use the same border handling and mix()
+// output as RNG on rings of different widths, so the effect of ring size on
+// quality can be measured in isolation. Each consumes its whole ring (one
+// word per Uint64) before stepping, and warms up with 4 steps per word, as
+// RNG does with its 16.
+
+// ringSeedConst returns the constant XORed into word i of a seeded ring
+// Multiples of the golden ratio keep the words distinct for any seed.
+func ringSeedConst(i int) uint64 {
+	return uint64(i) * 0x9e3779b97f4a7c15
+}
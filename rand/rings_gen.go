@@ -0,0 +1,237 @@
+// Code generated by gen_rings.go; DO NOT EDIT.
+
+package rand
+
+// Ring128 runs Rule 30 on a 128-bit ring (2 × 64-bit words)
+type Ring128 struct {
+	state [2]uint64
+	pos   int
+	tail  uint64
+	ntail int
+}
+
+// NewRing128 creates a new 128-bit Rule 30 generator from a seed
+func NewRing128(seed uint64) *Ring128 {
+	r := &Ring128{}
+	r.Reseed(seed)
+	return r
+}
+
+// Reseed resets the generator to the state NewRing128(seed) would produce
+func (r *Ring128) Reseed(seed uint64) {
+	for i := range r.state {
+		r.state[i] = seed ^ ringSeedConst(i)
+	}
+	r.pos = 0
+	r.tail, r.ntail = 0, 0
+	for i := 0; i < 4*len(r.state); i++ {
+		r.step()
+	}
+}
+
+// step applies radius-1 Rule 30 to the 128-bit ring
+//
+//go:noinline
+func (r *Ring128) step() {
+	s0 := r.state[0]
+	s1 := r.state[1]
+
+	b0_0, b63_0 := s0&1, s0>>63
+	b0_1, b63_1 := s1&1, s1>>63
+
+	r.state[0] = ((s0 >> 1) | (b0_1 << 63)) ^ (s0 | ((s0 << 1) | b63_1))
+	r.state[1] = ((s1 >> 1) | (b0_0 << 63)) ^ (s1 | ((s1 << 1) | b63_0))
+}
+
+// Uint64 returns a random uint64
+func (r *Ring128) Uint64() uint64 {
+	if r.pos == len(r.state) {
+		r.step()
+		r.pos = 0
+	}
+	out := mix(r.state[r.pos])
+	r.pos++
+	return out
+}
+
+// Read implements io.Reader like RNG.Read
+func (r *Ring128) Read(buf []byte) (n int, err error) {
+	readWords(buf, &r.tail, &r.ntail, r.Uint64, nil)
+	return len(buf), nil
+}
+
+// Ring512 runs Rule 30 on a 512-bit ring (8 × 64-bit words)
+type Ring512 struct {
+	state [8]uint64
+	pos   int
+	tail  uint64
+	ntail int
+}
+
+// NewRing512 creates a new 512-bit Rule 30 generator from a seed
+func NewRing512(seed uint64) *Ring512 {
+	r := &Ring512{}
+	r.Reseed(seed)
+	return r
+}
+
+// Reseed resets the generator to the state NewRing512(seed) would produce
+func (r *Ring512) Reseed(seed uint64) {
+	for i := range r.state {
+		r.state[i] = seed ^ ringSeedConst(i)
+	}
+	r.pos = 0
+	r.tail, r.ntail = 0, 0
+	for i := 0; i < 4*len(r.state); i++ {
+		r.step()
+	}
+}
+
+// step applies radius-1 Rule 30 to the 512-bit ring
+//
+//go:noinline
+func (r *Ring512) step() {
+	s0 := r.state[0]
+	s1 := r.state[1]
+	s2 := r.state[2]
+	s3 := r.state[3]
+	s4 := r.state[4]
+	s5 := r.state[5]
+	s6 := r.state[6]
+	s7 := r.state[7]
+
+	b0_0, b63_0 := s0&1, s0>>63
+	b0_1, b63_1 := s1&1, s1>>63
+	b0_2, b63_2 := s2&1, s2>>63
+	b0_3, b63_3 := s3&1, s3>>63
+	b0_4, b63_4 := s4&1, s4>>63
+	b0_5, b63_5 := s5&1, s5>>63
+	b0_6, b63_6 := s6&1, s6>>63
+	b0_7, b63_7 := s7&1, s7>>63
+
+	r.state[0] = ((s0 >> 1) | (b0_7 << 63)) ^ (s0 | ((s0 << 1) | b63_1))
+	r.state[1] = ((s1 >> 1) | (b0_0 << 63)) ^ (s1 | ((s1 << 1) | b63_2))
+	r.state[2] = ((s2 >> 1) | (b0_1 << 63)) ^ (s2 | ((s2 << 1) | b63_3))
+	r.state[3] = ((s3 >> 1) | (b0_2 << 63)) ^ (s3 | ((s3 << 1) | b63_4))
+	r.state[4] = ((s4 >> 1) | (b0_3 << 63)) ^ (s4 | ((s4 << 1) | b63_5))
+	r.state[5] = ((s5 >> 1) | (b0_4 << 63)) ^ (s5 | ((s5 << 1) | b63_6))
+	r.state[6] = ((s6 >> 1) | (b0_5 << 63)) ^ (s6 | ((s6 << 1) | b63_7))
+	r.state[7] = ((s7 >> 1) | (b0_6 << 63)) ^ (s7 | ((s7 << 1) | b63_0))
+}
+
+// Uint64 returns a random uint64
+func (r *Ring512) Uint64() uint64 {
+	if r.pos == len(r.state) {
+		r.step()
+		r.pos = 0
+	}
+	out := mix(r.state[r.pos])
+	r.pos++
+	return out
+}
+
+// Read implements io.Reader like RNG.Read
+func (r *Ring512) Read(buf []byte) (n int, err error) {
+	readWords(buf, &r.tail, &r.ntail, r.Uint64, nil)
+	return len(buf), nil
+}
+
+// Ring1024 runs Rule 30 on a 1024-bit ring (16 × 64-bit words)
+type Ring1024 struct {
+	state [16]uint64
+	pos   int
+	tail  uint64
+	ntail int
+}
+
+// NewRing1024 creates a new 1024-bit Rule 30 generator from a seed
+func NewRing1024(seed uint64) *Ring1024 {
+	r := &Ring1024{}
+	r.Reseed(seed)
+	return r
+}
+
+// Reseed resets the generator to the state NewRing1024(seed) would produce
+func (r *Ring1024) Reseed(seed uint64) {
+	for i := range r.state {
+		r.state[i] = seed ^ ringSeedConst(i)
+	}
+	r.pos = 0
+	r.tail, r.ntail = 0, 0
+	for i := 0; i < 4*len(r.state); i++ {
+		r.step()
+	}
+}
+
+// step applies radius-1 Rule 30 to the 1024-bit ring
+//
+//go:noinline
+func (r *Ring1024) step() {
+	s0 := r.state[0]
+	s1 := r.state[1]
+	s2 := r.state[2]
+	s3 := r.state[3]
+	s4 := r.state[4]
+	s5 := r.state[5]
+	s6 := r.state[6]
+	s7 := r.state[7]
+	s8 := r.state[8]
+	s9 := r.state[9]
+	s10 := r.state[10]
+	s11 := r.state[11]
+	s12 := r.state[12]
+	s13 := r.state[13]
+	s14 := r.state[14]
+	s15 := r.state[15]
+
+	b0_0, b63_0 := s0&1, s0>>63
+	b0_1, b63_1 := s1&1, s1>>63
+	b0_2, b63_2 := s2&1, s2>>63
+	b0_3, b63_3 := s3&1, s3>>63
+	b0_4, b63_4 := s4&1, s4>>63
+	b0_5, b63_5 := s5&1, s5>>63
+	b0_6, b63_6 := s6&1, s6>>63
+	b0_7, b63_7 := s7&1, s7>>63
+	b0_8, b63_8 := s8&1, s8>>63
+	b0_9, b63_9 := s9&1, s9>>63
+	b0_10, b63_10 := s10&1, s10>>63
+	b0_11, b63_11 := s11&1, s11>>63
+	b0_12, b63_12 := s12&1, s12>>63
+	b0_13, b63_13 := s13&1, s13>>63
+	b0_14, b63_14 := s14&1, s14>>63
+	b0_15, b63_15 := s15&1, s15>>63
+
+	r.state[0] = ((s0 >> 1) | (b0_15 << 63)) ^ (s0 | ((s0 << 1) | b63_1))
+	r.state[1] = ((s1 >> 1) | (b0_0 << 63)) ^ (s1 | ((s1 << 1) | b63_2))
+	r.state[2] = ((s2 >> 1) | (b0_1 << 63)) ^ (s2 | ((s2 << 1) | b63_3))
+	r.state[3] = ((s3 >> 1) | (b0_2 << 63)) ^ (s3 | ((s3 << 1) | b63_4))
+	r.state[4] = ((s4 >> 1) | (b0_3 << 63)) ^ (s4 | ((s4 << 1) | b63_5))
+	r.state[5] = ((s5 >> 1) | (b0_4 << 63)) ^ (s5 | ((s5 << 1) | b63_6))
+	r.state[6] = ((s6 >> 1) | (b0_5 << 63)) ^ (s6 | ((s6 << 1) | b63_7))
+	r.state[7] = ((s7 >> 1) | (b0_6 << 63)) ^ (s7 | ((s7 << 1) | b63_8))
+	r.state[8] = ((s8 >> 1) | (b0_7 << 63)) ^ (s8 | ((s8 << 1) | b63_9))
+	r.state[9] = ((s9 >> 1) | (b0_8 << 63)) ^ (s9 | ((s9 << 1) | b63_10))
+	r.state[10] = ((s10 >> 1) | (b0_9 << 63)) ^ (s10 | ((s10 << 1) | b63_11))
+	r.state[11] = ((s11 >> 1) | (b0_10 << 63)) ^ (s11 | ((s11 << 1) | b63_12))
+	r.state[12] = ((s12 >> 1) | (b0_11 << 63)) ^ (s12 | ((s12 << 1) | b63_13))
+	r.state[13] = ((s13 >> 1) | (b0_12 << 63)) ^ (s13 | ((s13 << 1) | b63_14))
+	r.state[14] = ((s14 >> 1) | (b0_13 << 63)) ^ (s14 | ((s14 << 1) | b63_15))
+	r.state[15] = ((s15 >> 1) | (b0_14 << 63)) ^ (s15 | ((s15 << 1) | b63_0))
+}
+
+// Uint64 returns a random uint64
+func (r *Ring1024) Uint64() uint64 {
+	if r.pos == len(r.state) {
+		r.step()
+		r.pos = 0
+	}
+	out := mix(r.state[r.pos])
+	r.pos++
+	return out
+}
+
+// Read implements io.Reader like RNG.Read
+func (r *Ring1024) Read(buf []byte) (n int, err error) {
+	readWords(buf, &r.tail, &r.ntail, r.Uint64, nil)
+	return len(buf), nil
+}
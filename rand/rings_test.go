@@ -0,0 +1,128 @@
+package rand
+
+import (
+	"io"
+	"testing"
+)
+
+// referenceStep applies radius-1 Rule 30 one bit at a time
+func referenceStep(words []uint64) []uint64 {
+	return referenceStepRadius(words, 1, func(nb []uint64) uint64 {
+		return nb[0] ^ (nb[1] | nb[2])
+	})
+}
+
+// referenceStepRadius applies a radius-r rule one bit at a time
+// rule receives the 2r+1 neighbourhood from leftmost to rightmost.
+// Bit k of word w sits at ring index 64w + 63 - k, so a cell's left
+// neighbour is the next bit up in its word (or bit 0 of the previous word)
+// and its right neighbour the next bit down (or bit 63 of the next word).
+func referenceStepRadius(words []uint64, radius int, rule func(nb []uint64) uint64) []uint64 {
+	n := 64 * len(words)
+	get := func(i int) uint64 {
+		i = (i%n + n) % n
+		return words[i/64] >> (63 - i%64) & 1
+	}
+	out := make([]uint64, len(words))
+	nb := make([]uint64, 2*radius+1)
+	for i := 0; i < n; i++ {
+		for j := range nb {
+			nb[j] = get(i - radius + j)
+		}
+		out[i/64] |= rule(nb) << (63 - i%64)
+	}
+	return out
+}
+
+func TestStepMatchesReference(t *testing.T) {
+	src := New(1)
+	for trial := 0; trial < 100; trial++ {
+		var r RNG
+		for i := range r.state {
+			r.state[i] = src.Uint64()
+		}
+		want := referenceStep(r.state[:])
+		r.step()
+		for i := range want {
+			if r.state[i] != want[i] {
+				t.Fatalf("trial %d word %d: %#x, want %#x", trial, i, r.state[i], want[i])
+			}
+		}
+	}
+}
+
+func TestRingStepsMatchReference(t *testing.T) {
+	src := New(2)
+	for trial := 0; trial < 50; trial++ {
+		var r128 Ring128
+		var r512 Ring512
+		var r1024 Ring1024
+		for _, s := range [][]uint64{r128.state[:], r512.state[:], r1024.state[:]} {
+			for i := range s {
+				s[i] = src.Uint64()
+			}
+		}
+		want128 := referenceStep(r128.state[:])
+		want512 := referenceStep(r512.state[:])
+		want1024 := referenceStep(r1024.state[:])
+		r128.step()
+		r512.step()
+		r1024.step()
+		for name, c := range map[string][2][]uint64{
+			"Ring128":  {r128.state[:], want128},
+			"Ring512":  {r512.state[:], want512},
+			"Ring1024": {r1024.state[:], want1024},
+		} {
+			for i := range c[0] {
+				if c[0][i] != c[1][i] {
+					t.Fatalf("%s trial %d word %d: %#x, want %#x", name, trial, i, c[0][i], c[1][i])
+				}
+			}
+		}
+	}
+}
+
+func TestRingOutputIsMixedState(t *testing.T) {
+	r := NewRing512(9)
+	state := r.state
+	for i := range state {
+		if got, want := r.Uint64(), mix(state[i]); got != want {
+			t.Fatalf("output %d: %#x, want %#x", i, got, want)
+		}
+	}
+	state = r.state
+	r.Uint64()
+	if r.state == state {
+		t.Fatal("ring did not step after consuming every word")
+	}
+}
+
+func TestRingReadChunkingInvariant(t *testing.T) {
+	want := make([]byte, 300)
+	NewRing1024(4).Read(want)
+	got := make([]byte, 0, 300)
+	r := NewRing1024(4)
+	for len(got) < 300 {
+		buf := make([]byte, min(7, 300-len(got)))
+		r.Read(buf)
+		got = append(got, buf...)
+	}
+	if string(got) != string(want) {
+		t.Fatal("Ring1024 byte stream depends on Read chunking")
+	}
+}
+
+func TestRingByteUniformity(t *testing.T) {
+	// A smoke test only; TestU01 via "ring30mix raw --gen=ringN" is the
+	// real comparison between widths
+	for name, r := range map[string]io.Reader{
+		"Ring128":  NewRing128(1),
+		"RNG":      New(1),
+		"Ring512":  NewRing512(1),
+		"Ring1024": NewRing1024(1),
+	} {
+		if chi2 := chiSquareBytes(t, r, 400_000); chi2 > chiSquare255 {
+			t.Errorf("%s byte chi-square = %.1f", name, chi2)
+		}
+	}
+}
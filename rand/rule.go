@@ -0,0 +1,135 @@
+package rand
+
+import "strings"
+
+// This file contains the generic elementary cellular automaton. A Wolfram
+// rule number is compiled into its algebraic normal form (XOR of ANDs of
+// the left/center/right words), which evaluates bit-parallel and
+// branch-free for any rule. RuleRNG runs it on the same 256-bit ring, with
+// the same seeding and mix() output, as RNG.
+
+// anfTerms names the ANF monomials, indexed by the bits l<<2 | c<<1 | r
+var anfTerms = [8]string{"1", "r", "c", "c&r", "l", "l&r", "l&c", "l&c&r"}
+
+// Rule is an elementary cellular automaton rule compiled for bit-parallel
+// evaluation
+type Rule struct {
+	number uint8
+	coef   [8]uint64 // ANF coefficients as all-zero or all-one masks
+}
+
+// CompileRule compiles a Wolfram rule number
+// Bit 4l+2c+r of number is the new cell for neighbourhood (l, c, r).
+func CompileRule(number uint8) Rule {
+	// Möbius transform from truth table to ANF coefficients
+	var a [8]uint8
+	for x := range a {
+		a[x] = number >> x & 1
+	}
+	for bit := 1; bit < 8; bit <<= 1 {
+		for x := range a {
+			if x&bit != 0 {
+				a[x] ^= a[x^bit]
+			}
+		}
+	}
+
+	r := Rule{number: number}
+	for m, v := range a {
+		r.coef[m] = -uint64(v)
+	}
+	return r
+}
+
+// Number returns the Wolfram rule number
+func (r Rule) Number() uint8 {
+	return r.number
+}
+
+// Apply evaluates the rule on 64 cells at once
+func (r Rule) Apply(left, center, right uint64) uint64 {
+	c := &r.coef
+	return c[0] ^ (c[1] & right) ^ (c[2] & center) ^ (c[3] & center & right) ^
+		(c[4] & left) ^ (c[5] & left & right) ^ (c[6] & left & center) ^
+		(c[7] & left & center & right)
+}
+
+// String returns the compiled expression, e.g. "r ^ c ^ c&r ^ l" for
+// Rule 30 (which is l XOR (c OR r))
+func (r Rule) String() string {
+	var terms []string
+	for m, c := range r.coef {
+		if c != 0 {
+			terms = append(terms, anfTerms[m])
+		}
+	}
+	if len(terms) == 0 {
+		return "0"
+	}
+	return strings.Join(terms, " ^ ")
+}
+
+// RuleRNG runs any elementary rule on a 256-bit ring
+// NewRule(30, seed) produces exactly the stream of New(seed); RNG remains
+// the faster choice for Rule 30 itself.
+type RuleRNG struct {
+	rule  Rule
+	state [4]uint64
+	pos   int
+	tail  uint64
+	ntail int
+}
+
+// NewRule creates a generator for a Wolfram rule number from a seed
+func NewRule(number uint8, seed uint64) *RuleRNG {
+	g := &RuleRNG{rule: CompileRule(number)}
+	g.Reseed(seed)
+	return g
+}
+
+// Rule returns the compiled rule
+func (g *RuleRNG) Rule() Rule {
+	return g.rule
+}
+
+// Reseed resets the generator to the state NewRule would produce
+func (g *RuleRNG) Reseed(seed uint64) {
+	for i := range g.state {
+		g.state[i] = seed ^ seedConst[i]
+	}
+	g.pos = 0
+	g.tail, g.ntail = 0, 0
+	for i := 0; i < 16; i++ {
+		g.step()
+	}
+}
+
+// step applies the rule to the 256-bit ring with RNG's border handling
+func (g *RuleRNG) step() {
+	s0, s1, s2, s3 := g.state[0], g.state[1], g.state[2], g.state[3]
+
+	b0_0, b0_1, b0_2, b0_3 := s0&1, s1&1, s2&1, s3&1
+	b63_0, b63_1, b63_2, b63_3 := s0>>63, s1>>63, s2>>63, s3>>63
+
+	g.state[0] = g.rule.Apply((s0>>1)|(b0_3<<63), s0, (s0<<1)|b63_1)
+	g.state[1] = g.rule.Apply((s1>>1)|(b0_0<<63), s1, (s1<<1)|b63_2)
+	g.state[2] = g.rule.Apply((s2>>1)|(b0_1<<63), s2, (s2<<1)|b63_3)
+	g.state[3] = g.rule.Apply((s3>>1)|(b0_2<<63), s3, (s3<<1)|b63_0)
+}
+
+// Uint64 returns a random uint64
+func (g *RuleRNG) Uint64() uint64 {
+	if g.pos == 4 {
+		g.step()
+		g.pos = 0
+	}
+	out := mix(g.state[g.pos])
+	g.pos++
+	return out
+}
+
+// Read implements io.Reader like RNG.Read
+func (g *RuleRNG) Read(buf []byte) (n int, err error) {
+	readWords(buf, &g.tail, &g.ntail, g.Uint64, nil)
+	return len(buf), nil
+}
@@ -0,0 +1,86 @@
+package rand
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCompileRuleTruthTable(t *testing.T) {
+	// Every rule must reproduce its own truth table on all 8 neighbourhoods
+	for n := 0; n < 256; n++ {
+		r := CompileRule(uint8(n))
+		if r.Number() != uint8(n) {
+			t.Fatalf("Number() = %d, want %d", r.Number(), n)
+		}
+		for x := 0; x < 8; x++ {
+			mask := func(bit int) uint64 { return -uint64(x >> bit & 1) }
+			got := r.Apply(mask(2), mask(1), mask(0))
+			if want := -uint64(n >> x & 1); got != want {
+				t.Fatalf("rule %d neighbourhood %03b: %#x, want %#x", n, x, got, want)
+			}
+		}
+	}
+}
+
+func TestRuleString(t *testing.T) {
+	for n, want := range map[uint8]string{
+		0:   "0",
+		30:  "r ^ c ^ c&r ^ l",
+		45:  "1 ^ r ^ c&r ^ l",
+		90:  "r ^ l",
+		150: "r ^ c ^ l",
+		255: "1",
+	} {
+		if got := CompileRule(n).String(); got != want {
+			t.Errorf("rule %d: %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestRule30MatchesRNG(t *testing.T) {
+	g, rng := NewRule(30, 777), New(777)
+	for i := 0; i < 1000; i++ {
+		if x, y := g.Uint64(), rng.Uint64(); x != y {
+			t.Fatalf("output %d: RuleRNG %#x, RNG %#x", i, x, y)
+		}
+	}
+	a, b := make([]byte, 101), make([]byte, 101)
+	g.Read(a)
+	rng.Read(b)
+	if !bytes.Equal(a, b) {
+		t.Fatal("RuleRNG(30).Read differs from RNG.Read")
+	}
+}
+
+func TestRuleStepMatchesReference(t *testing.T) {
+	// Rule 30 is the only rule with a bitwise reference; other rules are
+	// covered by the truth table test through the same step code
+	src := New(3)
+	g := NewRule(30, 0)
+	for trial := 0; trial < 20; trial++ {
+		for i := range g.state {
+			g.state[i] = src.Uint64()
+		}
+		want := referenceStep(g.state[:])
+		g.step()
+		for i := range want {
+			if g.state[i] != want[i] {
+				t.Fatalf("trial %d word %d: %#x, want %#x", trial, i, g.state[i], want[i])
+			}
+		}
+	}
+}
+
+func TestRuleQualityComparison(t *testing.T) {
+	// Logs a quick byte-uniformity figure per rule; run with -v to compare.
+	// The linear (105) and locally structured (73) rules show through the
+	// mixer, which is exactly what this comparison is for.
+	for _, n := range []uint8{30, 45, 73, 86, 105, 135} {
+		g := NewRule(n, 1)
+		chi2 := chiSquareBytes(t, g, 160_000)
+		t.Logf("rule %3d (%s): byte chi-square %.1f", n, g.Rule(), chi2)
+		if n == 30 && chi2 > chiSquare255 {
+			t.Errorf("rule 30 byte chi-square = %.1f", chi2)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package rand
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+)
+
+// This file contains constructors that seed the full 256-bit ring.
+// New(seed) only reaches 2^64 starting states; these reach all of them.
+
+// ErrDegenerateState is returned when a seed expands to a ring that is, or
+// quickly becomes, all-zero or rotationally symmetric
+var ErrDegenerateState = errors.New("rand: seed produces a degenerate ring state")
+
+// expansionIV holds the initial sponge lanes (hex digits of pi)
+var expansionIV = [4]uint64{
+	0x243f6a8885a308d3,
+	0x13198a2e03707344,
+	0xa4093822299f31d0,
+	0x082efa98ec4e6c89,
+}
+
+// splitmix is the SplitMix64 finalizer, a bijection on uint64
+func splitmix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// permute runs one round of the expansion permutation
+// Each lane is folded with its (already updated) neighbour and finalized.
+// Every update is invertible, so the round is a permutation of 256-bit space.
+func permute(s *[4]uint64) {
+	s[0] = splitmix(s[0] ^ bits.RotateLeft64(s[3], 23))
+	s[1] = splitmix(s[1] ^ bits.RotateLeft64(s[0], 23))
+	s[2] = splitmix(s[2] ^ bits.RotateLeft64(s[1], 23))
+	s[3] = splitmix(s[3] ^ bits.RotateLeft64(s[2], 23))
+}
+
+// Domain tags for expand, kept clear of any plausible byte length
+const (
+	tagSplit   = 1<<63 | 1
+	tagDerive  = 1<<63 | 2
+	tagCounter = 1<<63 | 3
+)
+
+// expand absorbs words into a 256-bit state
+// tag separates inputs that would otherwise absorb identically: the
+// seed constructors pass the input size in bytes (so trailing zero padding
+// matters), and other callers pass one of the domain tags above.
+func expand(words []uint64, tag uint64) [4]uint64 {
+	s := expansionIV
+	s[3] ^= tag
+	for len(words) > 0 {
+		for i := 0; i < 4 && i < len(words); i++ {
+			s[i] ^= words[i]
+		}
+		permute(&s)
+		permute(&s)
+		words = words[min(4, len(words)):]
+	}
+	// Extra rounds so that the last block is diffused as well as the first
+	for i := 0; i < 4; i++ {
+		permute(&s)
+	}
+	return s
+}
+
+// degenerate reports whether a ring state can't produce a useful stream
+// The all-zero ring is a fixed point of Rule 30, and a ring that repeats
+// every 128 bits (or any divisor) stays periodic forever, behaving like a
+// smaller ring.
+func degenerate(s [4]uint64) bool {
+	if s[0]|s[1]|s[2]|s[3] == 0 {
+		return true
+	}
+	return s[0] == s[2] && s[1] == s[3]
+}
+
+// init loads a full ring state, warms it up like New, and validates it
+func (r *RNG) init(s [4]uint64) error {
+	if degenerate(s) {
+		return ErrDegenerateState
+	}
+	r.state = s
+	r.pos = 0
+	r.tail, r.ntail = 0, 0
+	for i := 0; i < 16; i++ {
+		r.step()
+	}
+	if degenerate(r.state) {
+		return ErrDegenerateState
+	}
+	return nil
+}
+
+// NewFromSeeds creates a new RNG from 256 bits of seed material
+// The four words are expanded through a mixing permutation, so nearby
+// seeds give unrelated streams. It is equivalent to NewFromKey with the
+// words stored little-endian.
+func NewFromSeeds(a, b, c, d uint64) (*RNG, error) {
+	rng := &RNG{}
+	if err := rng.init(expand([]uint64{a, b, c, d}, 32)); err != nil {
+		return nil, err
+	}
+	return rng, nil
+}
+
+// NewFromKey creates a new RNG from a 256-bit key
+func NewFromKey(key [32]byte) (*RNG, error) {
+	return NewFromBytes(key[:])
+}
+
+// NewFromBytes creates a new RNG from arbitrary-length seed material
+// The input is absorbed 32 bytes at a time (the last block zero-padded)
+// together with its length, so every distinct input seeds a distinct
+// expansion.
+func NewFromBytes(seed []byte) (*RNG, error) {
+	words := make([]uint64, (len(seed)+7)/8)
+	for i := range words {
+		var block [8]byte
+		copy(block[:], seed[i*8:])
+		words[i] = binary.LittleEndian.Uint64(block[:])
+	}
+	rng := &RNG{}
+	if err := rng.init(expand(words, uint64(len(seed)))); err != nil {
+		return nil, err
+	}
+	return rng, nil
+}
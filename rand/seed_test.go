@@ -0,0 +1,91 @@
+package rand
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"testing"
+)
+
+func TestSeedConstructorsAgree(t *testing.T) {
+	var key [32]byte
+	for i := range key {
+		key[i] = byte(i*7 + 1)
+	}
+	a, b, c, d := binary.LittleEndian.Uint64(key[0:]), binary.LittleEndian.Uint64(key[8:]),
+		binary.LittleEndian.Uint64(key[16:]), binary.LittleEndian.Uint64(key[24:])
+
+	fromKey, err := NewFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromSeeds, err := NewFromSeeds(a, b, c, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromBytes, err := NewFromBytes(key[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 64; i++ {
+		x, y, z := fromKey.Uint64(), fromSeeds.Uint64(), fromBytes.Uint64()
+		if x != y || x != z {
+			t.Fatalf("output %d: key %#x, seeds %#x, bytes %#x", i, x, y, z)
+		}
+	}
+}
+
+func TestNewFromBytesLengthMatters(t *testing.T) {
+	inputs := [][]byte{nil, {0}, {0, 0}, make([]byte, 8), make([]byte, 9), make([]byte, 32), make([]byte, 33)}
+	seen := map[uint64]int{}
+	for i, in := range inputs {
+		rng, err := NewFromBytes(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v := rng.Uint64()
+		if j, ok := seen[v]; ok {
+			t.Fatalf("inputs %d and %d (lengths %d, %d) seed the same stream", j, i, len(inputs[j]), len(in))
+		}
+		seen[v] = i
+	}
+}
+
+func TestNewFromSeedsAdjacent(t *testing.T) {
+	// Seeds differing in one bit must give streams differing in about half
+	// their bits, with no word-level relationship
+	base, _ := NewFromSeeds(1, 2, 3, 4)
+	flip, _ := NewFromSeeds(1, 2, 3, 5)
+	var diff int
+	const words = 1000
+	for i := 0; i < words; i++ {
+		x, y := base.Uint64(), flip.Uint64()
+		if x == y {
+			t.Fatalf("adjacent seeds collide at output %d", i)
+		}
+		diff += bits.OnesCount64(x ^ y)
+	}
+	if frac := float64(diff) / (64 * words); frac < 0.48 || frac > 0.52 {
+		t.Errorf("adjacent seeds differ in %.3f of bits, want ~0.5", frac)
+	}
+}
+
+func TestDegenerateStatesRefused(t *testing.T) {
+	cases := map[string][4]uint64{
+		"zero":      {},
+		"ones":      {^uint64(0), ^uint64(0), ^uint64(0), ^uint64(0)},
+		"period128": {0x0123456789abcdef, 0xfedcba9876543210, 0x0123456789abcdef, 0xfedcba9876543210},
+		"period64":  {0xdeadbeef, 0xdeadbeef, 0xdeadbeef, 0xdeadbeef},
+	}
+	for name, s := range cases {
+		var r RNG
+		if err := r.init(s); !errors.Is(err, ErrDegenerateState) {
+			t.Errorf("%s: init returned %v, want ErrDegenerateState", name, err)
+		}
+	}
+
+	var r RNG
+	if err := r.init([4]uint64{1, 0, 0, 0}); err != nil {
+		t.Errorf("single-bit ring refused: %v", err)
+	}
+}
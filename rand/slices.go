@@ -0,0 +1,69 @@
+package rand
+
+// This file contains generic helpers for picking and reordering slice
+// elements. Like N, they are functions rather than methods because Go
+// methods can't have type parameters.
+
+// Choice returns a uniformly random element of s
+// Panics if s is empty
+func Choice[T any](r *RNG, s []T) T {
+	if len(s) == 0 {
+		panic("invalid argument to Choice")
+	}
+	return s[r.uint64n(uint64(len(s)))]
+}
+
+// ShuffleSlice pseudo-randomizes the order of the elements of s in place
+// It uses Fisher-Yates with unbiased bounded integers and consumes the
+// same draws as r.Shuffle(len(s), ...).
+func ShuffleSlice[T any](r *RNG, s []T) {
+	for i := len(s) - 1; i > 0; i-- {
+		j := r.uint64n(uint64(i + 1))
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// SampleK returns k distinct elements of s chosen without replacement, in
+// random order
+// For k much smaller than len(s) it uses Floyd's algorithm, which takes
+// O(k) time and space; otherwise a partial Fisher-Yates shuffle of the
+// indices. s is not modified.
+// Panics if k < 0 or k > len(s)
+func SampleK[T any](r *RNG, s []T, k int) []T {
+	n := len(s)
+	if k < 0 || k > n {
+		panic("invalid argument to SampleK")
+	}
+	out := make([]T, k)
+	if k > n/4 {
+		idx := make([]int, n)
+		for i := range idx {
+			idx[i] = i
+		}
+		for i := 0; i < k; i++ {
+			j := i + int(r.uint64n(uint64(n-i)))
+			idx[i], idx[j] = idx[j], idx[i]
+			out[i] = s[idx[i]]
+		}
+		return out
+	}
+
+	// Floyd: for j in n-k..n-1, add a random t in [0, j], or j itself if
+	// t was already taken
+	chosen := make(map[int]struct{}, k)
+	idx := make([]int, 0, k)
+	for j := n - k; j < n; j++ {
+		t := int(r.uint64n(uint64(j + 1)))
+		if _, dup := chosen[t]; dup {
+			t = j
+		}
+		chosen[t] = struct{}{}
+		idx = append(idx, t)
+	}
+	// Floyd's set is uniform but its order is not
+	ShuffleSlice(r, idx)
+	for i, t := range idx {
+		out[i] = s[t]
+	}
+	return out
+}
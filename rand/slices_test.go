@@ -0,0 +1,84 @@
+package rand
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestChoice(t *testing.T) {
+	rng := New(1)
+	s := make([]int, 256)
+	for i := range s {
+		s[i] = i
+	}
+	counts := make([]int, len(s))
+	const n = 256_000
+	for i := 0; i < n; i++ {
+		counts[Choice(rng, s)]++
+	}
+	if x2 := chiSquare(counts, n); x2 > chiSquare255 {
+		t.Errorf("Choice chi-square = %.1f", x2)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Choice of an empty slice did not panic")
+		}
+	}()
+	Choice(rng, []int{})
+}
+
+func TestShuffleSliceMatchesShuffle(t *testing.T) {
+	a := New(5).Perm(100)
+	b := make([]int, 100)
+	for i := range b {
+		b[i] = i
+	}
+	ShuffleSlice(New(5), b)
+	if !slices.Equal(a, b) {
+		t.Fatal("ShuffleSlice and Perm disagree")
+	}
+}
+
+func TestSampleK(t *testing.T) {
+	// k = 8 uses Floyd's algorithm, k = 60 the partial shuffle
+	const n, trials = 100, 30_000
+	s := make([]int, n)
+	for i := range s {
+		s[i] = i
+	}
+	rng := New(6)
+	for _, k := range []int{8, 60} {
+		included := make([]int, n)
+		first := make([]int, n)
+		for i := 0; i < trials; i++ {
+			got := SampleK(rng, s, k)
+			if len(got) != k {
+				t.Fatalf("SampleK returned %d items, want %d", len(got), k)
+			}
+			seen := map[int]bool{}
+			for _, v := range got {
+				if seen[v] {
+					t.Fatalf("SampleK returned %d twice", v)
+				}
+				seen[v] = true
+				included[v]++
+			}
+			first[got[0]]++
+		}
+		// Every element is equally likely to be chosen and to come first;
+		// 99 degrees of freedom, p = 0.001
+		if x2 := chiSquare(included, trials*k); x2 > 148.2 {
+			t.Errorf("k = %d: inclusion chi-square = %.1f", k, x2)
+		}
+		if x2 := chiSquare(first, trials); x2 > 148.2 {
+			t.Errorf("k = %d: order chi-square = %.1f", k, x2)
+		}
+	}
+	if got := SampleK(rng, s, 0); len(got) != 0 {
+		t.Fatalf("SampleK(0) = %v", got)
+	}
+	if got := SampleK(rng, s, n); len(got) != n {
+		t.Fatalf("SampleK(n) returned %d items", len(got))
+	}
+}
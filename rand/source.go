@@ -0,0 +1,52 @@
+package rand
+
+import (
+	mathrand "math/rand"
+	mathrandv2 "math/rand/v2"
+)
+
+// This file contains the adapters that let RNG plug into the standard
+// library's math/rand and math/rand/v2 packages.
+
+// RNG satisfies both source interfaces directly
+var (
+	_ mathrand.Source64 = (*RNG)(nil)
+	_ mathrand.Source64 = (*MixedRNG)(nil)
+	_ mathrandv2.Source = (*RNG)(nil)
+)
+
+// Seed resets the generator to the state New(uint64(seed)) would produce
+// This makes RNG a math/rand.Source, so rand.New(rng).Seed(x) behaves as
+// expected. Negative seeds are reinterpreted as their two's complement bits.
+func (r *RNG) Seed(seed int64) {
+	r.Reseed(uint64(seed))
+}
+
+// NewSource returns a math/rand.Source64 backed by ring30mix
+// It is the drop-in counterpart of math/rand.NewSource:
+//
+//	rng := mathrand.New(rand.NewSource(42))
+//
+// Like math/rand sources, the returned value is not safe for concurrent use.
+func NewSource(seed int64) mathrand.Source64 {
+	return New(uint64(seed))
+}
+
+// NewRand returns a *math/rand.Rand driven by ring30mix
+// Use it wherever an API insists on *math/rand.Rand, such as
+// testing/quick.Config.Rand.
+func NewRand(seed int64) *mathrand.Rand {
+	return mathrand.New(NewSource(seed))
+}
+
+// NewSourceV2 returns a math/rand/v2.Source backed by ring30mix
+//
+//	rng := mathrandv2.New(rand.NewSourceV2(42))
+func NewSourceV2(seed uint64) mathrandv2.Source {
+	return New(seed)
+}
+
+// NewRandV2 returns a *math/rand/v2.Rand driven by ring30mix
+func NewRandV2(seed uint64) *mathrandv2.Rand {
+	return mathrandv2.New(NewSourceV2(seed))
+}
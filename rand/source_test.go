@@ -0,0 +1,95 @@
+package rand
+
+import (
+	mathrand "math/rand"
+	mathrandv2 "math/rand/v2"
+	"testing"
+	"testing/quick"
+)
+
+func TestSeedMatchesNew(t *testing.T) {
+	rng := New(1)
+	for i := 0; i < 37; i++ {
+		rng.Uint64()
+	}
+	rng.Seed(12345)
+
+	want := New(12345)
+	for i := 0; i < 100; i++ {
+		if got, exp := rng.Uint64(), want.Uint64(); got != exp {
+			t.Fatalf("output %d after Seed: got %#x, want %#x", i, got, exp)
+		}
+	}
+}
+
+func TestReseedNegativeSeed(t *testing.T) {
+	a := New(0)
+	a.Seed(-1)
+	b := New(^uint64(0))
+	for i := 0; i < 16; i++ {
+		if a.Uint64() != b.Uint64() {
+			t.Fatalf("Seed(-1) differs from New(MaxUint64) at output %d", i)
+		}
+	}
+}
+
+func TestMathRandSource64(t *testing.T) {
+	r := mathrand.New(NewSource(42))
+	ref := New(42)
+	for i := 0; i < 100; i++ {
+		if got, want := r.Uint64(), ref.Uint64(); got != want {
+			t.Fatalf("math/rand Uint64 %d: got %#x, want %#x", i, got, want)
+		}
+	}
+	for i := 0; i < 100; i++ {
+		if got, want := r.Int63(), ref.Int63(); got != want {
+			t.Fatalf("math/rand Int63 %d: got %d, want %d", i, got, want)
+		}
+	}
+
+	// Seeding through math/rand must restart the stream
+	r.Seed(7)
+	ref = New(7)
+	if got, want := r.Uint64(), ref.Uint64(); got != want {
+		t.Fatalf("after Rand.Seed: got %#x, want %#x", got, want)
+	}
+}
+
+func TestMathRandV2Source(t *testing.T) {
+	r := mathrandv2.New(NewSourceV2(42))
+	ref := New(42)
+	for i := 0; i < 100; i++ {
+		if got, want := r.Uint64(), ref.Uint64(); got != want {
+			t.Fatalf("math/rand/v2 Uint64 %d: got %#x, want %#x", i, got, want)
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		if v := r.IntN(10); v < 0 || v >= 10 {
+			t.Fatalf("math/rand/v2 IntN(10) = %d", v)
+		}
+	}
+}
+
+func TestQuickConfigRand(t *testing.T) {
+	run := func() []uint32 {
+		var seen []uint32
+		f := func(x uint32) bool {
+			seen = append(seen, x)
+			return true
+		}
+		if err := quick.Check(f, &quick.Config{MaxCount: 20, Rand: NewRand(99)}); err != nil {
+			t.Fatal(err)
+		}
+		return seen
+	}
+
+	a, b := run(), run()
+	if len(a) != 20 || len(b) != 20 {
+		t.Fatalf("quick.Check ran %d and %d times, want 20", len(a), len(b))
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("quick.Check inputs not reproducible at %d: %d vs %d", i, a[i], b[i])
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package rand
+
+import (
+	"math"
+	"math/bits"
+	"testing"
+)
+
+// correlation returns the Pearson correlation between the Float64 streams
+// of a and b over n samples
+func correlation(a, b *RNG, n int) float64 {
+	var sx, sy, sxx, syy, sxy float64
+	for i := 0; i < n; i++ {
+		x, y := a.Float64(), b.Float64()
+		sx += x
+		sy += y
+		sxx += x * x
+		syy += y * y
+		sxy += x * y
+	}
+	fn := float64(n)
+	cov := sxy/fn - sx/fn*sy/fn
+	vx := sxx/fn - sx/fn*sx/fn
+	vy := syy/fn - sy/fn*sy/fn
+	return cov / math.Sqrt(vx*vy)
+}
+
+// bitAgreement returns the fraction of equal bits between the Uint64
+// streams of a and b over n samples
+func bitAgreement(a, b *RNG, n int) float64 {
+	var same int
+	for i := 0; i < n; i++ {
+		same += 64 - bits.OnesCount64(a.Uint64()^b.Uint64())
+	}
+	return float64(same) / float64(64*n)
+}
+
+// checkIndependent fails if two streams show correlation beyond chance
+func checkIndependent(t *testing.T, name string, a, b *RNG) {
+	t.Helper()
+	const n = 20000
+	// 5 sigma for a sample correlation of independent uniforms
+	if r := correlation(a.Clone(), b.Clone(), n); math.Abs(r) > 5/math.Sqrt(n) {
+		t.Errorf("%s: correlation %.4f", name, r)
+	}
+	if f := bitAgreement(a, b, n); math.Abs(f-0.5) > 5*0.5/math.Sqrt(64*n) {
+		t.Errorf("%s: bit agreement %.4f", name, f)
+	}
+}
+
+func TestSplitIndependent(t *testing.T) {
+	parent := New(1)
+	c1 := parent.Split()
+	c2 := parent.Split()
+	checkIndependent(t, "parent/child", parent.Clone(), c1.Clone())
+	checkIndependent(t, "siblings", c1.Clone(), c2.Clone())
+	checkIndependent(t, "grandchild", c1.Clone(), c1.Clone().Split())
+}
+
+func TestSplitAdjacentSeeds(t *testing.T) {
+	// Children of adjacent seeds must not inherit their parents' similarity
+	a, b := New(1000).Split(), New(1001).Split()
+	checkIndependent(t, "adjacent seeds", a, b)
+}
+
+func TestSplitReproducible(t *testing.T) {
+	a, b := New(9), New(9)
+	for i := 0; i < 4; i++ {
+		sameStream(t, a.Split(), b.Split(), 20)
+	}
+}
+
+func TestDeriveReproducible(t *testing.T) {
+	root := New(2026)
+	before := root.Clone()
+
+	x := root.Derive("worker", 3, "phase", "init")
+	y := root.Derive("worker", 3).Derive("phase", "init")
+	z := New(2026).Derive("worker", uint8(3), "phase", "init")
+	sameStream(t, x.Clone(), y, 50)
+	sameStream(t, x, z, 50)
+
+	// Derive must not advance the parent
+	sameStream(t, root, before, 50)
+}
+
+func TestDeriveDistinctLabels(t *testing.T) {
+	root := New(7)
+	paths := [][]any{
+		{},
+		{"worker"},
+		{"worker", 0},
+		{"worker", 1},
+		{"worker", -1},
+		{"worker", "1"},
+		{"worker", []byte("1")},
+		{"worker", true},
+		{"worker", false},
+		{"workers"},
+		{"work", "er"},
+	}
+	seen := map[uint64]int{}
+	for i, p := range paths {
+		v := root.Derive(p...).Uint64()
+		if j, ok := seen[v]; ok {
+			t.Fatalf("paths %v and %v derive the same stream", paths[j], p)
+		}
+		seen[v] = i
+	}
+
+	checkIndependent(t, "workers 0/1", root.Derive("worker", 0), root.Derive("worker", 1))
+	checkIndependent(t, "root/child", root.Clone(), root.Derive("worker", 0))
+}
+
+func TestDeriveUnsupportedLabel(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Derive(3.5) did not panic")
+		}
+	}()
+	New(1).Derive(3.5)
+}
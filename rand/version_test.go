@@ -0,0 +1,103 @@
+package rand
+
+import (
+	"math"
+	"testing"
+)
+
+func TestV1KeepsLegacySequence(t *testing.T) {
+	// Values produced by NormFloat64 and ExpFloat64 before versioning
+	want := []float64{
+		0.01986488995200715, -0.47937637875803846, -1.4410035832125043,
+		0.35136050417504294, 0.469332831749665, 1.4308055055448663,
+	}
+	r := NewVersion(2024, V1)
+	for i, w := range want {
+		var got float64
+		if i < 3 {
+			got = r.NormFloat64()
+		} else {
+			got = r.ExpFloat64()
+		}
+		if got != w {
+			t.Fatalf("V1 sample %d = %v, want %v", i, got, w)
+		}
+	}
+}
+
+func TestZigguratDistribution(t *testing.T) {
+	// Chi-square of 256 equiprobable bins, using the exact CDFs; the outer
+	// bins cover the tails beyond the base strips
+	const n, bins = 2_000_000, 256
+	r := New(2025)
+	norm := make([]int, bins)
+	exp := make([]int, bins)
+	for i := 0; i < n; i++ {
+		p := 0.5 * math.Erfc(-r.NormFloat64()/math.Sqrt2)
+		norm[min(int(p*bins), bins-1)]++
+		p = -math.Expm1(-r.ExpFloat64())
+		exp[min(int(p*bins), bins-1)]++
+	}
+	if x2 := chiSquare(norm, n); x2 > chiSquare255 {
+		t.Errorf("NormFloat64 chi-square = %.1f", x2)
+	}
+	if x2 := chiSquare(exp, n); x2 > chiSquare255 {
+		t.Errorf("ExpFloat64 chi-square = %.1f", x2)
+	}
+}
+
+func TestZigguratMoments(t *testing.T) {
+	const n = 1_000_000
+	r := New(7)
+	var ns, nss, es, ess float64
+	for i := 0; i < n; i++ {
+		x := r.NormFloat64()
+		ns += x
+		nss += x * x
+		y := r.ExpFloat64()
+		if y < 0 {
+			t.Fatalf("ExpFloat64 returned %v", y)
+		}
+		es += y
+		ess += y * y
+	}
+	check := func(name string, got, want, sigma float64) {
+		if math.Abs(got-want) > 5*sigma {
+			t.Errorf("%s = %.5f, want %.5f", name, got, want)
+		}
+	}
+	check("normal mean", ns/n, 0, 1/math.Sqrt(n))
+	check("normal variance", nss/n-(ns/n)*(ns/n), 1, math.Sqrt(2.0/n))
+	check("exponential mean", es/n, 1, 1/math.Sqrt(n))
+	check("exponential variance", ess/n-(es/n)*(es/n), 1, math.Sqrt(8.0/n))
+}
+
+func TestVersion(t *testing.T) {
+	r := New(1)
+	if r.Version() != LatestVersion {
+		t.Fatalf("New follows %v, want %v", r.Version(), LatestVersion)
+	}
+	r.SetVersion(V1)
+	if r.Split().Version() != V1 || r.Derive("x").Version() != V1 {
+		t.Fatal("children do not inherit the version")
+	}
+
+	data, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored RNG
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Version() != V1 {
+		t.Fatalf("restored version %v, want v1", restored.Version())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("SetVersion(0) did not panic")
+		}
+	}()
+	r.SetVersion(0)
+}